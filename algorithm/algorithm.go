@@ -0,0 +1,246 @@
+// Package algorithm implements generic algorithms over iterators
+//
+// The functions returning an iterator are lazy: values are computed as
+// the result is ranged over, and stopping the iteration early stops the
+// input as well. The input may be any iterator.Seq, such as a container's
+// Values(), slices.Values(s) for a slice, or chans.ToSeq(c) for a channel.
+// chans.FromSeq turns a result back into a channel:
+//
+//	sq := algorithm.Map(vec.Values(), func(v int) int { return v * v })
+//	for v := range algorithm.Take(sq, 3) {
+//		// do something with v
+//	}
+package algorithm
+
+import (
+	"cmp"
+
+	"github.com/redouan-rhazouani/goboost/iterator"
+	"github.com/redouan-rhazouani/goboost/tuple"
+)
+
+// Map returns an iterator over fn(v) for each value v of seq
+func Map[T, U any](seq iterator.Seq[T], fn func(T) U) iterator.Seq[U] {
+	return func(yield func(U) bool) {
+		for v := range seq {
+			if !yield(fn(v)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns an iterator over the values of seq satisfying pred
+func Filter[T any](seq iterator.Seq[T], pred func(T) bool) iterator.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if pred(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// FlatMap returns an iterator over the values of fn(v) for each value v of seq
+func FlatMap[T, U any](seq iterator.Seq[T], fn func(T) iterator.Seq[U]) iterator.Seq[U] {
+	return func(yield func(U) bool) {
+		for v := range seq {
+			for u := range fn(v) {
+				if !yield(u) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over the values of seq paired with their position
+func Enumerate[T any](seq iterator.Seq[T]) iterator.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for v := range seq {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Zip returns an iterator over pairs of values of a and b at the same position.
+// It stops as soon as one of them is exhausted.
+func Zip[A, B any](a iterator.Seq[A], b iterator.Seq[B]) iterator.Seq[tuple.Pair[A, B]] {
+	return func(yield func(tuple.Pair[A, B]) bool) {
+		it := iterator.Pull(b)
+		defer it.Stop()
+		for x := range a {
+			y, ok := it.Next()
+			if !ok || !yield(tuple.MakePair(x, y)) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk returns an iterator over consecutive chunks of size values of seq.
+// The last chunk may be shorter. Each chunk is a new slice.
+// Chunk panics if size is less than 1.
+func Chunk[T any](seq iterator.Seq[T], size int) iterator.Seq[[]T] {
+	if size < 1 {
+		panic("algorithm: chunk size must be positive")
+	}
+	return func(yield func([]T) bool) {
+		var chunk []T
+		for v := range seq {
+			if chunk == nil {
+				chunk = make([]T, 0, size)
+			}
+			chunk = append(chunk, v)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = nil
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Window returns an iterator over the sliding windows of size consecutive values of seq,
+// moving by one value at a time. Nothing is yielded if seq has less than size values.
+// Each window is a new slice.
+// Window panics if size is less than 1.
+func Window[T any](seq iterator.Seq[T], size int) iterator.Seq[[]T] {
+	if size < 1 {
+		panic("algorithm: window size must be positive")
+	}
+	return func(yield func([]T) bool) {
+		buf := make([]T, 0, size)
+		for v := range seq {
+			if len(buf) == size {
+				copy(buf, buf[1:])
+				buf = buf[:size-1]
+			}
+			buf = append(buf, v)
+			if len(buf) == size && !yield(append([]T(nil), buf...)) {
+				return
+			}
+		}
+	}
+}
+
+// Take returns an iterator over the first n values of seq
+func Take[T any](seq iterator.Seq[T], n int) iterator.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+			if i++; i == n {
+				return
+			}
+		}
+	}
+}
+
+// Skip returns an iterator over the values of seq after the first n
+func Skip[T any](seq iterator.Seq[T], n int) iterator.Seq[T] {
+	return func(yield func(T) bool) {
+		i := 0
+		for v := range seq {
+			if i < n {
+				i++
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Reduce combines the values of seq from left to right using fn.
+// It returns false if seq is empty.
+func Reduce[T any](seq iterator.Seq[T], fn func(acc, v T) T) (T, bool) {
+	var acc T
+	ok := false
+	for v := range seq {
+		if ok {
+			acc = fn(acc, v)
+		} else {
+			acc, ok = v, true
+		}
+	}
+	return acc, ok
+}
+
+// Fold combines init and the values of seq from left to right using fn
+func Fold[T, U any](seq iterator.Seq[T], init U, fn func(acc U, v T) U) U {
+	for v := range seq {
+		init = fn(init, v)
+	}
+	return init
+}
+
+// Any reports whether at least one value of seq satisfies pred
+func Any[T any](seq iterator.Seq[T], pred func(T) bool) bool {
+	for v := range seq {
+		if pred(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// All reports whether every value of seq satisfies pred.
+// It returns true if seq is empty.
+func All[T any](seq iterator.Seq[T], pred func(T) bool) bool {
+	for v := range seq {
+		if !pred(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// MinBy returns the first value of seq with the smallest key.
+// It returns false if seq is empty.
+func MinBy[T any, K cmp.Ordered](seq iterator.Seq[T], key func(T) K) (T, bool) {
+	return extremeBy(seq, key, func(k, best K) bool { return cmp.Less(k, best) })
+}
+
+// MaxBy returns the first value of seq with the largest key.
+// It returns false if seq is empty.
+func MaxBy[T any, K cmp.Ordered](seq iterator.Seq[T], key func(T) K) (T, bool) {
+	return extremeBy(seq, key, func(k, best K) bool { return cmp.Less(best, k) })
+}
+
+// extremeBy returns the first value of seq whose key is better than all others
+func extremeBy[T any, K cmp.Ordered](seq iterator.Seq[T], key func(T) K, better func(k, best K) bool) (T, bool) {
+	var r T
+	var best K
+	ok := false
+	for v := range seq {
+		if k := key(v); !ok || better(k, best) {
+			r, best, ok = v, k, true
+		}
+	}
+	return r, ok
+}
+
+// GroupBy returns the values of seq grouped by key, in their order within each group
+func GroupBy[T any, K comparable](seq iterator.Seq[T], key func(T) K) map[K][]T {
+	m := make(map[K][]T)
+	for v := range seq {
+		k := key(v)
+		m[k] = append(m[k], v)
+	}
+	return m
+}
@@ -0,0 +1,242 @@
+package algorithm
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/redouan-rhazouani/goboost/chans"
+	"github.com/redouan-rhazouani/goboost/container/forward_list"
+	"github.com/redouan-rhazouani/goboost/container/set"
+	"github.com/redouan-rhazouani/goboost/container/vector"
+	"github.com/redouan-rhazouani/goboost/iterator"
+	"github.com/redouan-rhazouani/goboost/tuple"
+)
+
+// counted returns an iterator over 0..n-1 and the number of values yielded so far
+func counted(n int) (iterator.Seq[int], *int) {
+	yielded := 0
+	return func(yield func(int) bool) {
+		for i := 0; i < n; i++ {
+			yielded++
+			if !yield(i) {
+				return
+			}
+		}
+	}, &yielded
+}
+
+func TestLazy(t *testing.T) {
+	square := func(v int) int { return v * v }
+	even := func(v int) bool { return v%2 == 0 }
+	twice := func(v int) iterator.Seq[int] { return iterator.Of(v, v) }
+	tests := []struct {
+		name string
+		seq  iterator.Seq[int]
+		want []int
+	}{
+		{"Map", Map(iterator.Of(1, 2, 3), square), []int{1, 4, 9}},
+		{"MapEmpty", Map(iterator.Empty[int](), square), nil},
+		{"Filter", Filter(iterator.Of(1, 2, 3, 4), even), []int{2, 4}},
+		{"FlatMap", FlatMap(iterator.Of(1, 2), twice), []int{1, 1, 2, 2}},
+		{"Take", Take(iterator.Of(1, 2, 3), 2), []int{1, 2}},
+		{"TakeMore", Take(iterator.Of(1, 2), 5), []int{1, 2}},
+		{"TakeZero", Take(iterator.Of(1, 2), 0), nil},
+		{"Skip", Skip(iterator.Of(1, 2, 3), 2), []int{3}},
+		{"SkipMore", Skip(iterator.Of(1, 2), 5), nil},
+	}
+	for _, tt := range tests {
+		if got := slices.Collect(tt.seq); !slices.Equal(got, tt.want) {
+			t.Errorf("%s got=%v want=%v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEarlyExit(t *testing.T) {
+	id := func(v int) int { return v }
+	tests := []struct {
+		name string
+		op   func(iterator.Seq[int]) iterator.Seq[int]
+	}{
+		{"Map", func(s iterator.Seq[int]) iterator.Seq[int] { return Map(s, id) }},
+		{"Filter", func(s iterator.Seq[int]) iterator.Seq[int] { return Filter(s, func(int) bool { return true }) }},
+		{"FlatMap", func(s iterator.Seq[int]) iterator.Seq[int] {
+			return FlatMap(s, func(v int) iterator.Seq[int] { return iterator.Of(v) })
+		}},
+		{"Skip", func(s iterator.Seq[int]) iterator.Seq[int] { return Skip(s, 1) }},
+		{"Chunk", func(s iterator.Seq[int]) iterator.Seq[int] {
+			return Map(Chunk(s, 1), func(c []int) int { return c[0] })
+		}},
+		{"Window", func(s iterator.Seq[int]) iterator.Seq[int] {
+			return Map(Window(s, 1), func(w []int) int { return w[0] })
+		}},
+	}
+	for _, tt := range tests {
+		seq, yielded := counted(100)
+		for range Take(tt.op(seq), 3) {
+		}
+		if *yielded > 4 {
+			t.Errorf("%s yielded=%d values, want at most 4", tt.name, *yielded)
+		}
+	}
+}
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		xs   []int
+		size int
+		want [][]int
+	}{
+		{nil, 2, nil},
+		{[]int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{[]int{1, 2, 3}, 2, [][]int{{1, 2}, {3}}},
+		{[]int{1, 2}, 5, [][]int{{1, 2}}},
+	}
+	for _, tt := range tests {
+		got := slices.Collect(Chunk(slices.Values(tt.xs), tt.size))
+		if !slices.EqualFunc(got, tt.want, slices.Equal) {
+			t.Errorf("Chunk(%v, %d) got=%v want=%v", tt.xs, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		xs   []int
+		size int
+		want [][]int
+	}{
+		{[]int{1, 2}, 3, nil},
+		{[]int{1, 2, 3}, 3, [][]int{{1, 2, 3}}},
+		{[]int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {2, 3}, {3, 4}}},
+	}
+	for _, tt := range tests {
+		got := slices.Collect(Window(slices.Values(tt.xs), tt.size))
+		if !slices.EqualFunc(got, tt.want, slices.Equal) {
+			t.Errorf("Window(%v, %d) got=%v want=%v", tt.xs, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestPanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"Chunk":  func() { Chunk(iterator.Of(1), 0) },
+		"Window": func() { Window(iterator.Of(1), 0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s must panic for size 0", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestEnumerateZip(t *testing.T) {
+	i := 0
+	for k, v := range Enumerate(iterator.Of("a", "b")) {
+		if k != i || v != []string{"a", "b"}[i] {
+			t.Errorf("Enumerate got=(%d, %s) at %d", k, v, i)
+		}
+		i++
+	}
+	got := slices.Collect(Zip(iterator.Of(1, 2, 3), iterator.Of("a", "b")))
+	want := []tuple.Pair[int, string]{tuple.MakePair(1, "a"), tuple.MakePair(2, "b")}
+	if !slices.Equal(got, want) {
+		t.Errorf("Zip got=%v want=%v", got, want)
+	}
+	got = slices.Collect(Zip(iterator.Of(1), iterator.Of("a", "b")))
+	if len(got) != 1 {
+		t.Errorf("Zip got=%v want=[(1, a)]", got)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	add := func(a, b int) int { return a + b }
+	if v, ok := Reduce(iterator.Of(1, 2, 3), add); !ok || v != 6 {
+		t.Errorf("Reduce got=(%d, %v) want=(6, true)", v, ok)
+	}
+	if v, ok := Reduce(iterator.Empty[int](), add); ok {
+		t.Errorf("Reduce got=(%d, %v) want=(0, false)", v, ok)
+	}
+	s := Fold(iterator.Of(1, 2, 3), "", func(acc string, v int) string { return acc + strconv.Itoa(v) })
+	if s != "123" {
+		t.Errorf("Fold got=%q want=%q", s, "123")
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	pos := func(v int) bool { return v > 0 }
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"Any", Any(iterator.Of(-1, 2), pos), true},
+		{"AnyNone", Any(iterator.Of(-1, -2), pos), false},
+		{"AnyEmpty", Any(iterator.Empty[int](), pos), false},
+		{"All", All(iterator.Of(1, 2), pos), true},
+		{"AllNot", All(iterator.Of(1, -2), pos), false},
+		{"AllEmpty", All(iterator.Empty[int](), pos), true},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s got=%v want=%v", tt.name, tt.got, tt.want)
+		}
+	}
+	seq, yielded := counted(100)
+	if !Any(seq, func(v int) bool { return v == 1 }) || *yielded != 2 {
+		t.Errorf("Any must stop at the first match, yielded=%d", *yielded)
+	}
+}
+
+func TestMinMaxBy(t *testing.T) {
+	words := iterator.Of("bb", "a", "ccc", "d", "eee")
+	length := func(s string) int { return len(s) }
+	if v, ok := MinBy(words, length); !ok || v != "a" {
+		t.Errorf("MinBy got=(%q, %v) want=(a, true)", v, ok)
+	}
+	if v, ok := MaxBy(words, length); !ok || v != "ccc" {
+		t.Errorf("MaxBy got=(%q, %v) want=(ccc, true)", v, ok)
+	}
+	if _, ok := MinBy(iterator.Empty[string](), length); ok {
+		t.Errorf("MinBy of an empty sequence must return false")
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	g := GroupBy(iterator.Of(1, 2, 3, 4, 5), func(v int) bool { return v%2 == 0 })
+	if !slices.Equal(g[true], []int{2, 4}) || !slices.Equal(g[false], []int{1, 3, 5}) || len(g) != 2 {
+		t.Errorf("GroupBy got=%v", g)
+	}
+}
+
+func TestContainers(t *testing.T) {
+	double := func(v int) int { return 2 * v }
+	vec := vector.Vector[int]{1, 2, 3}
+	if got := slices.Collect(Map(vec.Values(), double)); !slices.Equal(got, []int{2, 4, 6}) {
+		t.Errorf("Vector got=%v", got)
+	}
+	l := forward_list.New[int]()
+	l.PushBack(1)
+	l.PushBack(2)
+	if got := slices.Collect(Map(l.Values(), double)); !slices.Equal(got, []int{2, 4}) {
+		t.Errorf("ForwardList got=%v", got)
+	}
+	s := set.FromSlice([]int{1, 2, 3})
+	if sum := Fold(s.Values(), 0, func(a, v int) int { return a + v }); sum != 6 {
+		t.Errorf("Set sum got=%d want=6", sum)
+	}
+}
+
+func TestChans(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := chans.FromSeq(ctx, Filter(chans.ToSeq(chans.FromSlice([]int{1, 2, 3, 4})), func(v int) bool { return v > 2 }))
+	if got := chans.ToSlice(c); !slices.Equal(got, []int{3, 4}) {
+		t.Errorf("got=%v want=[3 4]", got)
+	}
+}
@@ -0,0 +1,189 @@
+package chans
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrClosed is returned when using a closed Broker or when all channels
+// are closed before a value is received, see FirstOf
+var ErrClosed = errors.New("chans: closed")
+
+// Broker is an in-process publish/subscribe event bus.
+//
+// Values are published on a topic of type K and delivered to every subscriber
+// whose topic matches. Each subscriber has its own buffer and overflow policy:
+// with Block, Publish waits while the buffer of a matching subscriber is full,
+// with DropNewest and DropOldest the value is discarded for that subscriber only.
+// A Broker is safe for concurrent use.
+type Broker[K comparable, T any] struct {
+	size     int
+	overflow Overflow
+
+	mu      sync.RWMutex // guards subs and closed
+	subs    map[*subscriber[K, T]]struct{}
+	closed  bool
+	quit    chan struct{} // closed on Shutdown to abort blocked publishers
+	once    sync.Once     // closes quit
+	wg      sync.WaitGroup
+	dropped atomic.Uint64
+}
+
+// subscriber delivers the values of matching topics through a buffer
+type subscriber[K comparable, T any] struct {
+	match  func(K) bool
+	in     chan T
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.RWMutex // held for reading while sending to in
+	closed bool         // in is closed
+}
+
+// NewBroker returns a broker whose Subscribe and SubscribeAll buffer up to size
+// values per subscriber and apply the overflow policy when the buffer is full.
+func NewBroker[K comparable, T any](size int, overflow Overflow) *Broker[K, T] {
+	return &Broker[K, T]{
+		size:     size,
+		overflow: overflow,
+		subs:     make(map[*subscriber[K, T]]struct{}),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving the values published on topic,
+// and a function to unsubscribe. Unsubscribing closes the channel,
+// discarding the buffered values. It is safe to call it more than once.
+func (b *Broker[K, T]) Subscribe(topic K) (<-chan T, func()) {
+	return b.SubscribeFunc(func(k K) bool { return k == topic }, b.size, b.overflow)
+}
+
+// SubscribeAll is like Subscribe for the values published on any topic
+func (b *Broker[K, T]) SubscribeAll() (<-chan T, func()) {
+	return b.SubscribeFunc(func(K) bool { return true }, b.size, b.overflow)
+}
+
+// SubscribeFunc is like Subscribe for the values published on topics satisfying match,
+// with a buffer of up to size values, at least one, and its own overflow policy.
+// If the broker is closed, the returned channel is closed.
+func (b *Broker[K, T]) SubscribeFunc(match func(K) bool, size int, overflow Overflow) (<-chan T, func()) {
+	if size < 1 {
+		size = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &subscriber[K, T]{match: match, in: make(chan T), ctx: ctx, cancel: cancel}
+	out := make(chan T)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		cancel()
+		close(out)
+		return out, func() {}
+	}
+	b.subs[s] = struct{}{}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		pump(ctx, s.in, out, size, overflow, &b.dropped)
+	}()
+	unsubscribe := func() {
+		cancel() // first, to release publishers blocked on s
+		b.mu.Lock()
+		delete(b.subs, s)
+		b.mu.Unlock()
+	}
+	return out, unsubscribe
+}
+
+// Publish delivers v to the subscribers of topic.
+// It returns ErrClosed if the broker is closed.
+// The lock of the broker is not held while waiting for a full subscriber,
+// so subscribers may subscribe and unsubscribe meanwhile.
+func (b *Broker[K, T]) Publish(topic K, v T) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrClosed
+	}
+	var subs []*subscriber[K, T]
+	for s := range b.subs {
+		if s.match(topic) {
+			subs = append(subs, s)
+		}
+	}
+	b.mu.RUnlock()
+	for _, s := range subs {
+		if !s.send(v, b.quit) {
+			return ErrClosed
+		}
+	}
+	return nil
+}
+
+// send delivers v to s unless s is unsubscribed.
+// It returns false if s is closed or quit is closed first.
+func (s *subscriber[K, T]) send(v T, quit <-chan struct{}) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return false
+	}
+	select {
+	case s.in <- v:
+	case <-s.ctx.Done(): // unsubscribed
+	case <-quit:
+		return false
+	}
+	return true
+}
+
+// Dropped returns the number of values discarded so far by the overflow policies of the subscribers
+func (b *Broker[K, T]) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+// Shutdown closes the broker.
+//
+// Publishers blocked on a full subscriber are aborted and new values are rejected.
+// Shutdown then waits until the subscribers have received their buffered values
+// and closes their channels. If ctx is done first, the remaining buffered values
+// are discarded, the channels are closed and ctx.Err() is returned.
+// Subsequent calls return ErrClosed.
+func (b *Broker[K, T]) Shutdown(ctx context.Context) error {
+	b.once.Do(func() { close(b.quit) })
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrClosed
+	}
+	b.closed = true
+	subs := b.subs
+	b.subs = nil
+	b.mu.Unlock()
+	// publishers blocked on a subscriber are aborted by quit
+	for s := range subs {
+		s.mu.Lock()
+		s.closed = true
+		close(s.in)
+		s.mu.Unlock()
+	}
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	for s := range subs {
+		s.cancel()
+	}
+	<-done
+	return err
+}
@@ -0,0 +1,188 @@
+package chans
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+)
+
+func TestBroker(t *testing.T) {
+	ctx := context.Background()
+	b := NewBroker[string, int](10, Block)
+	a, _ := b.Subscribe("a")
+	all, _ := b.SubscribeAll()
+	prefix, _ := b.SubscribeFunc(func(k string) bool { return strings.HasPrefix(k, "user.") }, 10, Block)
+	for i, topic := range []string{"a", "b", "user.created", "a", "user.deleted"} {
+		if err := b.Publish(topic, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got [][]int
+	var wg sync.WaitGroup
+	for _, c := range []<-chan int{a, all, prefix} {
+		got = append(got, nil)
+		wg.Add(1)
+		go func(i int, c <-chan int) {
+			defer wg.Done()
+			got[i] = chanstest.Collect(t, c, timeout)
+		}(len(got)-1, c)
+	}
+	if err := b.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown got=%v want nil", err)
+	}
+	wg.Wait()
+	want := [][]int{{0, 3}, {0, 1, 2, 3, 4}, {2, 4}}
+	for i := range want {
+		if !equal(got[i], want[i]) {
+			t.Errorf("subscriber %d got=%v want=%v", i, got[i], want[i])
+		}
+	}
+	if err := b.Publish("a", 1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Publish after Shutdown got=%v want=%v", err, ErrClosed)
+	}
+	if err := b.Shutdown(ctx); !errors.Is(err, ErrClosed) {
+		t.Errorf("Shutdown twice got=%v want=%v", err, ErrClosed)
+	}
+	c, unsubscribe := b.Subscribe("a")
+	unsubscribe()
+	if _, ok := <-c; ok {
+		t.Errorf("Subscribe after Shutdown must return a closed channel")
+	}
+}
+
+func TestBrokerUnsubscribe(t *testing.T) {
+	b := NewBroker[int, int](1, Block)
+	c, unsubscribe := b.Subscribe(1)
+	if err := b.Publish(1, 1); err != nil { // fills the buffer
+		t.Fatal(err)
+	}
+	published := make(chan error)
+	go func() { published <- b.Publish(1, 2) }() // blocks on the full buffer
+	unsubscribe()
+	unsubscribe()
+	if err := <-published; err != nil {
+		t.Errorf("Publish got=%v want nil", err)
+	}
+	for range c { // closed by unsubscribe
+	}
+	if err := b.Publish(1, 3); err != nil {
+		t.Errorf("Publish without subscribers got=%v want nil", err)
+	}
+	if err := b.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown got=%v want nil", err)
+	}
+}
+
+func TestBrokerSubscribeWhilePublishing(t *testing.T) {
+	b := NewBroker[string, int](1, Block)
+	a, unsubscribe := b.Subscribe("a")
+	if err := b.Publish("a", 1); err != nil { // fills the buffer
+		t.Fatal(err)
+	}
+	published := make(chan error)
+	go func() { published <- b.Publish("a", 2) }() // blocks on the full buffer
+	time.Sleep(10 * time.Millisecond)              // lets the publisher block
+	subscribed := make(chan (<-chan int))
+	go func() {
+		c, _ := b.Subscribe("b")
+		subscribed <- c
+	}()
+	c, _ := chanstest.Receive(t, subscribed, timeout)
+	if err := b.Publish("b", 3); err != nil {
+		t.Errorf("Publish got=%v want nil", err)
+	}
+	if v, _ := chanstest.Receive(t, c, timeout); v != 3 {
+		t.Errorf("got=%d want=3", v)
+	}
+	unsubscribe()
+	if err, _ := chanstest.Receive(t, published, timeout); err != nil {
+		t.Errorf("blocked Publish got=%v want nil", err)
+	}
+	for range a {
+	}
+	if err := b.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown got=%v want nil", err)
+	}
+}
+
+func TestBrokerOverflow(t *testing.T) {
+	b := NewBroker[int, int](2, DropOldest)
+	oldest, _ := b.Subscribe(1)
+	newest, _ := b.SubscribeFunc(func(k int) bool { return k == 1 }, 2, DropNewest)
+	for i := 1; i <= 5; i++ {
+		if err := b.Publish(1, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	go b.Shutdown(context.Background())
+	if got := chanstest.Collect(t, oldest, timeout); !equal(got, []int{4, 5}) {
+		t.Errorf("DropOldest got=%v want=[4 5]", got)
+	}
+	if got := chanstest.Collect(t, newest, timeout); !equal(got, []int{1, 2}) {
+		t.Errorf("DropNewest got=%v want=[1 2]", got)
+	}
+	if n := b.Dropped(); n != 6 {
+		t.Errorf("Dropped() got=%d want=6", n)
+	}
+}
+
+func TestBrokerShutdownTimeout(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	b := NewBroker[int, int](1, Block)
+	c, _ := b.Subscribe(1)
+	if err := b.Publish(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	published := make(chan error)
+	go func() { published <- b.Publish(1, 2) }() // blocks on the full buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	// the subscriber never reads
+	if err := b.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown got=%v want=%v", err, context.DeadlineExceeded)
+	}
+	if err := <-published; !errors.Is(err, ErrClosed) {
+		t.Errorf("blocked Publish got=%v want=%v", err, ErrClosed)
+	}
+	for range c {
+	}
+	check()
+}
+
+func TestBrokerConcurrent(t *testing.T) {
+	b := NewBroker[int, int](4, DropOldest)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(topic int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.Publish(topic%2, j)
+			}
+		}(i)
+		go func(topic int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				c, unsubscribe := b.Subscribe(topic % 2)
+				select {
+				case <-c:
+				case <-time.After(time.Millisecond):
+				}
+				unsubscribe()
+				for range c {
+				}
+			}
+		}(i)
+	}
+	all, _ := b.SubscribeAll()
+	go Drain(all)
+	wg.Wait()
+	if err := b.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown got=%v want nil", err)
+	}
+}
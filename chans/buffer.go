@@ -0,0 +1,167 @@
+package chans
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Unbounded is a channel with an unlimited buffer.
+//
+// Values sent on In are buffered in a ring that grows as needed,
+// so producers never wait for consumers reading from Out.
+// Closing In closes Out once the buffered values have been received.
+type Unbounded[T any] struct {
+	in  chan T
+	out chan T
+}
+
+// NewUnbounded returns a new unbounded channel.
+// Its goroutine stops and Out is closed, discarding the buffered values, when ctx is done.
+// Producers must then stop sending on In.
+func NewUnbounded[T any](ctx context.Context) *Unbounded[T] {
+	u := &Unbounded[T]{make(chan T), make(chan T)}
+	go pump(ctx, u.in, u.out, -1, Block, nil)
+	return u
+}
+
+// In returns the channel on which values are sent
+func (u *Unbounded[T]) In() chan<- T {
+	return u.in
+}
+
+// Out returns the channel on which values are received
+func (u *Unbounded[T]) Out() <-chan T {
+	return u.out
+}
+
+// Bounded is a channel buffering up to size values with an overflow policy.
+//
+// When the buffer is full, Block makes producers wait like a buffered channel,
+// while DropNewest and DropOldest discard a value and count it as dropped.
+// Closing In closes Out once the buffered values have been received.
+type Bounded[T any] struct {
+	in      chan T
+	out     chan T
+	dropped atomic.Uint64
+}
+
+// NewBounded returns a new channel buffering up to size values, at least one.
+// Its goroutine stops and Out is closed, discarding the buffered values, when ctx is done.
+// Producers must then stop sending on In.
+func NewBounded[T any](ctx context.Context, size int, overflow Overflow) *Bounded[T] {
+	if size < 1 {
+		size = 1
+	}
+	b := &Bounded[T]{in: make(chan T), out: make(chan T)}
+	go pump(ctx, b.in, b.out, size, overflow, &b.dropped)
+	return b
+}
+
+// In returns the channel on which values are sent
+func (b *Bounded[T]) In() chan<- T {
+	return b.in
+}
+
+// Out returns the channel on which values are received
+func (b *Bounded[T]) Out() <-chan T {
+	return b.out
+}
+
+// Dropped returns the number of values discarded so far
+func (b *Bounded[T]) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+// pump moves values from in to out through a ring buffer holding up to size values,
+// an unlimited number if size < 0. Values discarded by the overflow policy are counted in dropped.
+// out is closed when in is closed and the buffer is empty or when ctx is done.
+func pump[T any](ctx context.Context, in <-chan T, out chan<- T, size int, overflow Overflow, dropped *atomic.Uint64) {
+	defer close(out)
+	var q ring[T]
+	for in != nil || q.len() > 0 {
+		full := size >= 0 && q.len() >= size
+		recv := in
+		if full && overflow == Block {
+			recv = nil
+		}
+		var sendc chan<- T
+		var next T
+		if q.len() > 0 {
+			sendc, next = out, q.front()
+		}
+		select {
+		case v, ok := <-recv:
+			if !ok {
+				in = nil
+				continue
+			}
+			if full {
+				dropped.Add(1)
+				if overflow == DropNewest {
+					continue
+				}
+				q.pop()
+			}
+			q.push(v)
+		case sendc <- next:
+			q.pop()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ring is a FIFO queue backed by a circular buffer that grows and shrinks as needed
+type ring[T any] struct {
+	buf  []T
+	head int // index of the first element
+	n    int // number of elements
+}
+
+func (r *ring[T]) len() int {
+	return r.n
+}
+
+// front returns the first element, the ring must not be empty
+func (r *ring[T]) front() T {
+	return r.buf[r.head]
+}
+
+// at returns the i-th element, 0 <= i < r.len()
+func (r *ring[T]) at(i int) T {
+	return r.buf[(r.head+i)%len(r.buf)]
+}
+
+// push appends v at the back of the ring
+func (r *ring[T]) push(v T) {
+	if r.n == len(r.buf) {
+		r.resize(2*len(r.buf) + 8)
+	}
+	r.buf[(r.head+r.n)%len(r.buf)] = v
+	r.n++
+}
+
+// pop removes and returns the first element, the ring must not be empty
+func (r *ring[T]) pop() T {
+	var zero T
+	v := r.buf[r.head]
+	r.buf[r.head] = zero // avoid memory leaks
+	r.head = (r.head + 1) % len(r.buf)
+	r.n--
+	if len(r.buf) > 64 && r.n < len(r.buf)/4 {
+		r.resize(len(r.buf) / 2)
+	}
+	return v
+}
+
+// resize moves the elements to a new buffer of capacity n >= r.n
+func (r *ring[T]) resize(n int) {
+	buf := make([]T, n)
+	end := r.head + r.n
+	if end > len(r.buf) {
+		end = len(r.buf)
+	}
+	k := copy(buf, r.buf[r.head:end])
+	copy(buf[k:], r.buf[:r.n-k])
+	r.buf, r.head = buf, 0
+}
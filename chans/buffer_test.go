@@ -0,0 +1,186 @@
+package chans
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+)
+
+func TestRing(t *testing.T) {
+	var r ring[int]
+	var want []int // reference queue
+	v := 0
+	// interleave pushes and pops to wrap around while growing and shrinking
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 300; i++ {
+			r.push(v)
+			want = append(want, v)
+			v++
+			if i%3 == 0 {
+				if x := r.pop(); x != want[0] {
+					t.Fatalf("pop got=%d want=%d", x, want[0])
+				}
+				want = want[1:]
+			}
+		}
+		for r.len() > 0 {
+			if x := r.pop(); x != want[0] {
+				t.Fatalf("pop got=%d want=%d", x, want[0])
+			}
+			want = want[1:]
+		}
+		if len(want) != 0 {
+			t.Fatalf("ring is empty, %d values missing", len(want))
+		}
+		if n := len(r.buf); n > 128 {
+			t.Errorf("empty ring capacity got=%d want<=128", n)
+		}
+	}
+}
+
+func TestUnbounded(t *testing.T) {
+	u := NewUnbounded[int](context.Background())
+	n := 10000
+	for i := 0; i < n; i++ { // never blocks without consumer
+		u.In() <- i
+	}
+	close(u.In())
+	i := 0
+	for v := range u.Out() {
+		if v != i {
+			t.Fatalf("got=%d want=%d", v, i)
+		}
+		i++
+	}
+	if i != n {
+		t.Errorf("number of values got=%d want=%d", i, n)
+	}
+}
+
+func TestUnboundedConcurrent(t *testing.T) {
+	u := NewUnbounded[int](context.Background())
+	producers, n := 8, 1000
+	var wg sync.WaitGroup
+	wg.Add(producers)
+	for p := 0; p < producers; p++ {
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				u.In() <- p*n + i
+			}
+		}(p)
+	}
+	go func() {
+		wg.Wait()
+		close(u.In())
+	}()
+	last := make([]int, producers)
+	for i := range last {
+		last[i] = -1
+	}
+	count := 0
+	for v := range u.Out() {
+		p, i := v/n, v%n
+		if i <= last[p] {
+			t.Fatalf("producer %d: got %d after %d", p, i, last[p])
+		}
+		last[p] = i
+		count++
+	}
+	if count != producers*n {
+		t.Errorf("number of values got=%d want=%d", count, producers*n)
+	}
+}
+
+func TestBounded(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		overflow Overflow
+		want     []int
+	}{
+		{DropNewest, []int{1, 2}},
+		{DropOldest, []int{4, 5}},
+	}
+	for _, tc := range tests {
+		b := NewBounded[int](ctx, 2, tc.overflow)
+		for _, v := range []int{1, 2, 3, 4, 5} {
+			b.In() <- v // the consumer is not ready
+		}
+		close(b.In())
+		if got := chanstest.Collect(t, b.Out(), timeout); !equal(got, tc.want) {
+			t.Errorf("overflow=%d got=%v want=%v", tc.overflow, got, tc.want)
+		}
+		if n := b.Dropped(); n != 3 {
+			t.Errorf("overflow=%d Dropped() got=%d want=3", tc.overflow, n)
+		}
+	}
+}
+
+func TestBoundedBlock(t *testing.T) {
+	b := NewBounded[int](context.Background(), 2, Block)
+	b.In() <- 1
+	b.In() <- 2
+	select {
+	case b.In() <- 3:
+		t.Fatalf("send on a full buffer must block")
+	default:
+	}
+	if v := <-b.Out(); v != 1 {
+		t.Errorf("got=%d want=1", v)
+	}
+	b.In() <- 3
+	close(b.In())
+	if got := chanstest.Collect(t, b.Out(), timeout); !equal(got, []int{2, 3}) {
+		t.Errorf("got=%v want=[2 3]", got)
+	}
+	if n := b.Dropped(); n != 0 {
+		t.Errorf("Dropped() got=%d want=0", n)
+	}
+}
+
+func TestBufferCancel(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	u := NewUnbounded[int](ctx)
+	b := NewBounded[int](ctx, 1, Block)
+	u.In() <- 1
+	b.In() <- 1
+	cancel()
+	check()
+	for range u.Out() {
+	}
+	for range b.Out() {
+	}
+}
+
+func BenchmarkBuffers(b *testing.B) {
+	ctx := context.Background()
+	run := func(b *testing.B, in chan<- int, out <-chan int) {
+		go func() {
+			for i := 0; i < b.N; i++ {
+				in <- i
+			}
+			close(in)
+		}()
+		for range out {
+		}
+	}
+	b.Run("Chan", func(b *testing.B) {
+		c := make(chan int, 128)
+		run(b, c, c)
+	})
+	b.Run("Unbounded", func(b *testing.B) {
+		u := NewUnbounded[int](ctx)
+		run(b, u.in, u.out)
+	})
+	b.Run("Bounded/Block", func(b *testing.B) {
+		u := NewBounded[int](ctx, 128, Block)
+		run(b, u.in, u.out)
+	})
+	b.Run("Bounded/DropOldest", func(b *testing.B) {
+		u := NewBounded[int](ctx, 128, DropOldest)
+		run(b, u.in, u.out)
+	})
+}
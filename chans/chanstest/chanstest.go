@@ -0,0 +1,71 @@
+// Package chanstest provides utilities for testing code built on channels
+//
+// The helpers fail the test instead of blocking forever, FakeClock lets
+// time based functions run deterministically, and LeakCheck and
+// VerifyTestMain report goroutines that outlive a test.
+package chanstest
+
+import (
+	"testing"
+	"time"
+)
+
+// Receive returns the next value received from c within timeout.
+// ok is false if c is closed.
+// The test fails if nothing is received within timeout.
+func Receive[T any](t testing.TB, c <-chan T, timeout time.Duration) (v T, ok bool) {
+	t.Helper()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case v, ok = <-c:
+	case <-timer.C:
+		t.Errorf("no value received within %v", timeout)
+	}
+	return v, ok
+}
+
+// Collect returns the values received from c until it is closed.
+// The test fails if c is not closed within timeout,
+// in which case the values received so far are returned.
+// It is safe to call Collect from any goroutine.
+func Collect[T any](t testing.TB, c <-chan T, timeout time.Duration) []T {
+	t.Helper()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	a := []T{}
+	for {
+		select {
+		case v, ok := <-c:
+			if !ok {
+				return a
+			}
+			a = append(a, v)
+		case <-timer.C:
+			t.Errorf("channel not closed within %v, received %v", timeout, a)
+			return a
+		}
+	}
+}
+
+// AssertClosed fails the test if c receives a value or is not closed within timeout
+func AssertClosed[T any](t testing.TB, c <-chan T, timeout time.Duration) {
+	t.Helper()
+	if v, ok := Receive(t, c, timeout); ok {
+		t.Errorf("channel must be closed, received %v", v)
+	}
+}
+
+// AssertNoValue fails the test if a value is ready to be received from c or c is closed
+func AssertNoValue[T any](t testing.TB, c <-chan T) {
+	t.Helper()
+	select {
+	case v, ok := <-c:
+		if ok {
+			t.Errorf("channel must be empty, received %v", v)
+		} else {
+			t.Errorf("channel must be empty, it is closed")
+		}
+	default:
+	}
+}
@@ -0,0 +1,162 @@
+package chanstest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+// recorder is a testing.TB recording failures instead of reporting them
+type recorder struct {
+	testing.TB
+	mu     sync.Mutex
+	failed []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed = append(r.failed, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Failed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.failed) > 0
+}
+
+func TestReceive(t *testing.T) {
+	c := make(chan int, 1)
+	c <- 1
+	if v, ok := Receive(t, c, time.Second); v != 1 || !ok {
+		t.Errorf("Receive got=(%v, %v) want=(1, true)", v, ok)
+	}
+	r := &recorder{TB: t}
+	if _, ok := Receive(r, c, time.Millisecond); ok || !r.Failed() {
+		t.Errorf("Receive must fail on timeout")
+	}
+	close(c)
+	if _, ok := Receive(t, c, time.Second); ok {
+		t.Errorf("Receive of a closed channel got ok=true")
+	}
+}
+
+func TestCollect(t *testing.T) {
+	c := make(chan int, 2)
+	c <- 1
+	c <- 2
+	r := &recorder{TB: t}
+	if got := Collect(r, c, time.Millisecond); len(got) != 2 || !r.Failed() {
+		t.Errorf("Collect must return the values and fail on timeout got=%v", got)
+	}
+	c <- 3
+	close(c)
+	if got := Collect(t, c, time.Second); len(got) != 1 || got[0] != 3 {
+		t.Errorf("Collect got=%v want=[3]", got)
+	}
+}
+
+func TestAssertClosed(t *testing.T) {
+	c := make(chan int, 1)
+	r := &recorder{TB: t}
+	AssertClosed(r, c, time.Millisecond)
+	if !r.Failed() {
+		t.Errorf("AssertClosed must fail for an open channel")
+	}
+	r = &recorder{TB: t}
+	c <- 1
+	AssertClosed(r, c, time.Second)
+	if !r.Failed() {
+		t.Errorf("AssertClosed must fail for a channel with values")
+	}
+	close(c)
+	AssertClosed(t, c, time.Second)
+}
+
+func TestAssertNoValue(t *testing.T) {
+	c := make(chan int, 1)
+	AssertNoValue(t, c)
+	c <- 1
+	r := &recorder{TB: t}
+	if AssertNoValue(r, c); !r.Failed() {
+		t.Errorf("AssertNoValue must fail for a channel with values")
+	}
+	close(c)
+	r = &recorder{TB: t}
+	if AssertNoValue(r, c); !r.Failed() {
+		t.Errorf("AssertNoValue must fail for a closed channel")
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	c := NewFakeClock()
+	start := c.Now()
+	timer := c.NewTimer(2 * time.Second)
+	ticker := c.NewTicker(time.Second)
+	if n := c.Active(); n != 2 {
+		t.Errorf("Active() got=%d want=2", n)
+	}
+	c.Advance(time.Second)
+	if tick, _ := Receive(t, ticker.C(), time.Second); !tick.Equal(start.Add(time.Second)) {
+		t.Errorf("tick got=%v want=%v", tick, start.Add(time.Second))
+	}
+	AssertNoValue(t, timer.C())
+	c.Advance(time.Second)
+	if v, _ := Receive(t, timer.C(), time.Second); !v.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("timer got=%v want=%v", v, start.Add(2*time.Second))
+	}
+	if timer.Stop() {
+		t.Errorf("Stop() of an expired timer got=true want=false")
+	}
+	if timer.Reset(time.Second) {
+		t.Errorf("Reset() of an expired timer got=true want=false")
+	}
+	if !timer.Stop() {
+		t.Errorf("Stop() of an active timer got=false want=true")
+	}
+	ticker.Stop()
+	if n := c.Active(); n != 0 {
+		t.Errorf("Active() got=%d want=0", n)
+	}
+	if d := c.Now().Sub(start); d != 2*time.Second {
+		t.Errorf("elapsed got=%v want=%v", d, 2*time.Second)
+	}
+}
+
+func TestFakeClockBlockUntil(t *testing.T) {
+	c := NewFakeClock()
+	ctx := clock.With(context.Background(), c)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		timer := clock.From(ctx).NewTimer(time.Second)
+		<-timer.C()
+	}()
+	c.BlockUntil(1)
+	c.Advance(time.Second)
+	Receive(t, done, time.Second)
+}
+
+func TestLeakCheck(t *testing.T) {
+	LeakTimeout = 10 * time.Millisecond
+	defer func() { LeakTimeout = time.Second }()
+	r := &recorder{TB: t}
+	check := LeakCheck(r)
+	stop := make(chan struct{})
+	go func() { <-stop }()
+	check()
+	if !r.Failed() {
+		t.Errorf("LeakCheck must report the running goroutine")
+	}
+	close(stop)
+	defer LeakCheck(t)()
+	done := make(chan struct{})
+	go func() { close(done) }()
+	<-done
+}
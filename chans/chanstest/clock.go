@@ -0,0 +1,142 @@
+package chanstest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+// FakeClock is a clock.Clock whose time only moves on Advance.
+//
+// Attach it to the context of the function under test with clock.With.
+// Since timers are created by other goroutines, call BlockUntil before
+// Advance to make sure the timers to fire exist.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeTimer
+}
+
+// NewFakeClock returns a fake clock set to 2000-01-01 00:00:00 UTC
+func NewFakeClock() *FakeClock {
+	c := &FakeClock{now: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current time of the fake clock
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer creates a timer firing once the clock has been advanced by d
+func (c *FakeClock) NewTimer(d time.Duration) clock.Timer {
+	return c.add(d, 0)
+}
+
+// NewTicker creates a ticker firing each time the clock has been advanced by d
+func (c *FakeClock) NewTicker(d time.Duration) clock.Ticker {
+	return fakeTicker{c.add(d, d)}
+}
+
+// Advance moves the time forward by d and fires the expired timers in order.
+// Like real ones, a timer or ticker drops the time if its channel is full.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	end := c.now.Add(d)
+	for {
+		var next *fakeTimer
+		for _, w := range c.waiters {
+			if !w.when.After(end) && (next == nil || w.when.Before(next.when)) {
+				next = w
+			}
+		}
+		if next == nil {
+			break
+		}
+		c.now = next.when
+		select {
+		case next.c <- c.now:
+		default:
+		}
+		if next.period > 0 {
+			next.when = next.when.Add(next.period)
+		} else {
+			c.remove(next)
+		}
+	}
+	c.now = end
+}
+
+// BlockUntil waits until at least n timers or tickers are active
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// Active returns the number of active timers and tickers
+func (c *FakeClock) Active() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+func (c *FakeClock) add(d, period time.Duration) *fakeTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1), when: c.now.Add(d), period: period}
+	c.waiters = append(c.waiters, t)
+	c.cond.Broadcast()
+	return t
+}
+
+// remove removes t from the waiters and reports whether it was active
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	for i, w := range c.waiters {
+		if w == t {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock  *FakeClock
+	c      chan time.Time
+	when   time.Time
+	period time.Duration
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	active := c.remove(t)
+	t.when = c.now.Add(d)
+	c.waiters = append(c.waiters, t)
+	c.cond.Broadcast()
+	return active
+}
+
+type fakeTicker struct{ t *fakeTimer }
+
+func (t fakeTicker) C() <-chan time.Time { return t.t.c }
+
+func (t fakeTicker) Stop() { t.t.Stop() }
@@ -0,0 +1,93 @@
+package chanstest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// LeakTimeout is how long the leak checkers wait for goroutines to exit
+var LeakTimeout = time.Second
+
+// LeakCheck records the running goroutines and returns a function failing the
+// test if other goroutines are still running after LeakTimeout.
+// Typical use is
+//
+//	defer chanstest.LeakCheck(t)()
+//
+// Goroutines of tests running in parallel are reported as leaks.
+func LeakCheck(t testing.TB) func() {
+	t.Helper()
+	before := goroutines()
+	return func() {
+		t.Helper()
+		if leaks := waitLeaks(before); len(leaks) > 0 {
+			t.Errorf("%d leaked goroutines:\n\n%s", len(leaks), bytes.Join(leaks, []byte("\n\n")))
+		}
+	}
+}
+
+// VerifyTestMain runs the tests of m and exits with a failure
+// if goroutines started by the tests are still running afterwards.
+// Call it from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		chanstest.VerifyTestMain(m)
+//	}
+func VerifyTestMain(m *testing.M) {
+	before := goroutines()
+	code := m.Run()
+	if leaks := waitLeaks(before); code == 0 && len(leaks) > 0 {
+		fmt.Fprintf(os.Stderr, "chanstest: %d leaked goroutines:\n\n%s\n", len(leaks), bytes.Join(leaks, []byte("\n\n")))
+		code = 1
+	}
+	os.Exit(code)
+}
+
+// waitLeaks waits up to LeakTimeout for the goroutines missing from before to exit
+// and returns the stacks of those still running
+func waitLeaks(before map[int][]byte) [][]byte {
+	deadline := time.Now().Add(LeakTimeout)
+	for {
+		var leaks [][]byte
+		for id, stack := range goroutines() {
+			if _, ok := before[id]; !ok {
+				leaks = append(leaks, stack)
+			}
+		}
+		if len(leaks) == 0 || time.Now().After(deadline) {
+			return leaks
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// goroutines returns the stacks of the running goroutines by id, except the calling one
+func goroutines() map[int][]byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	stacks := bytes.Split(buf, []byte("\n\n"))
+	m := make(map[int][]byte, len(stacks)-1)
+	for _, stack := range stacks[1:] { // the first one is the calling goroutine
+		// "goroutine 42 [chan receive]:"
+		fields := bytes.Fields(stack)
+		if len(fields) < 2 {
+			continue
+		}
+		if id, err := strconv.Atoi(string(fields[1])); err == nil {
+			m[id] = stack
+		}
+	}
+	return m
+}
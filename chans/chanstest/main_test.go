@@ -0,0 +1,7 @@
+package chanstest
+
+import "testing"
+
+func TestMain(m *testing.M) {
+	VerifyTestMain(m)
+}
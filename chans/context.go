@@ -0,0 +1,112 @@
+package chans
+
+import (
+	"context"
+	"reflect"
+)
+
+// DrainContext drains channel c until it is closed or ctx is done.
+// It returns ctx.Err() if ctx is done before c is closed.
+func DrainContext[T any](ctx context.Context, c <-chan T) error {
+	for {
+		select {
+		case _, ok := <-c:
+			if !ok {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// MergeContext merges pair of channels into a single one.
+// The returned channel is closed when both inputs are closed or ctx is done.
+func MergeContext[T any](ctx context.Context, c1, c2 <-chan T) <-chan T {
+	r := make(chan T)
+	go func(c1, c2 <-chan T, r chan<- T) {
+		defer close(r)
+		for c1 != nil || c2 != nil {
+			var v T
+			var ok bool
+			select {
+			case v, ok = <-c1:
+				if !ok {
+					c1 = nil
+					continue
+				}
+			case v, ok = <-c2:
+				if !ok {
+					c2 = nil
+					continue
+				}
+			case <-ctx.Done():
+				return
+			}
+			if !send(ctx, r, v) {
+				return
+			}
+		}
+	}(c1, c2, r)
+	return r
+}
+
+// MergeNContext merges any number of channels into a single one like MergeN.
+// The returned channel is closed when all inputs are closed or ctx is done.
+func MergeNContext[T any](ctx context.Context, cs ...<-chan T) <-chan T {
+	r := make(chan T)
+	// the first case is reserved for ctx.Done()
+	cases := make([]reflect.SelectCase, 1, len(cs)+1)
+	cases[0] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	for _, c := range cs {
+		if c != nil {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c)})
+		}
+	}
+	go func(cases []reflect.SelectCase, r chan<- T) {
+		defer close(r)
+		for len(cases) > 1 {
+			i, v, ok := reflect.Select(cases)
+			if i == 0 {
+				return
+			}
+			if !ok {
+				n := len(cases) - 1
+				cases[i], cases[n] = cases[n], reflect.SelectCase{}
+				cases = cases[:n]
+				continue
+			}
+			x, _ := v.Interface().(T) // nil interface values yield T's zero value
+			if !send(ctx, r, x) {
+				return
+			}
+		}
+	}(cases, r)
+	return r
+}
+
+// RepeatContext repeats value v n times or until ctx is done.
+// Unlike Repeat the returned channel is unbuffered.
+func RepeatContext[T any](ctx context.Context, v T, n int) <-chan T {
+	r := make(chan T)
+	go func(r chan<- T, v T, n int) {
+		defer close(r)
+		for ; n > 0; n-- {
+			if !send(ctx, r, v) {
+				return
+			}
+		}
+	}(r, v, n)
+	return r
+}
+
+// send sends v on c unless ctx is done first.
+// It reports whether v was sent.
+func send[T any](ctx context.Context, c chan<- T, v T) bool {
+	select {
+	case c <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
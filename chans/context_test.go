@@ -0,0 +1,74 @@
+package chans
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+)
+
+func TestDrainContext(t *testing.T) {
+	ctx := context.Background()
+	if err := DrainContext(ctx, Repeat(1, 10)); err != nil {
+		t.Errorf("DrainContext got=%v want nil", err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := DrainContext(ctx, make(chan int)); !errors.Is(err, context.Canceled) {
+		t.Errorf("DrainContext got=%v want %v", err, context.Canceled)
+	}
+}
+
+func TestMergeContext(t *testing.T) {
+	n := 20
+	ctx := context.Background()
+	a := make([]int, 0, 2*n)
+	for v := range MergeContext(ctx, RepeatContext(ctx, 1, n), RepeatContext(ctx, 2, n)) {
+		a = append(a, v)
+	}
+	if m := len(a); m != 2*n {
+		t.Errorf("Number of elements got=%d want=%d", m, 2*n)
+	}
+	a = a[:0]
+	for v := range MergeNContext(ctx, RepeatContext(ctx, 1, n), RepeatContext(ctx, 2, n), nil) {
+		a = append(a, v)
+	}
+	if m := len(a); m != 2*n {
+		t.Errorf("Number of elements got=%d want=%d", m, 2*n)
+	}
+}
+
+func TestContextLeaks(t *testing.T) {
+	tests := map[string]func(ctx context.Context) <-chan int{
+		"MergeContext": func(ctx context.Context) <-chan int {
+			return MergeContext(ctx, RepeatContext(ctx, 1, 100), RepeatContext(ctx, 2, 100))
+		},
+		"MergeNContext": func(ctx context.Context) <-chan int {
+			return MergeNContext(ctx, RepeatContext(ctx, 1, 100), RepeatContext(ctx, 2, 100))
+		},
+		"MergeContextBlockedInputs": func(ctx context.Context) <-chan int {
+			return MergeContext(ctx, make(chan int), nil)
+		},
+		"MergeNContextBlockedInputs": func(ctx context.Context) <-chan int {
+			return MergeNContext(ctx, make(chan int), make(chan int))
+		},
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			check := chanstest.LeakCheck(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			c := fn(ctx)
+			select {
+			case <-c: // consume at most one value, then walk away
+			case <-time.After(time.Millisecond):
+			}
+			cancel()
+			check()
+			// output is closed deterministically
+			for range c {
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package chans
+
+import (
+	"context"
+
+	"github.com/redouan-rhazouani/goboost/container/forward_list"
+	"github.com/redouan-rhazouani/goboost/container/set"
+	"github.com/redouan-rhazouani/goboost/container/vector"
+	"github.com/redouan-rhazouani/goboost/iterator"
+)
+
+// ToSlice returns the values received from c until it is closed
+func ToSlice[T any](c <-chan T) []T {
+	var a []T
+	for v := range c {
+		a = append(a, v)
+	}
+	return a
+}
+
+// ToSliceContext returns the values received from c until it is closed or ctx is done.
+// If ctx is done first, the values received so far are returned with ctx.Err().
+func ToSliceContext[T any](ctx context.Context, c <-chan T) ([]T, error) {
+	var a []T
+	err := collectContext(ctx, c, func(v T) { a = append(a, v) })
+	return a, err
+}
+
+// ToVector returns a vector with the values received from c until it is closed
+func ToVector[T any](c <-chan T) vector.Vector[T] {
+	return ToSlice(c)
+}
+
+// ToVectorContext is like ToSliceContext but returns a vector
+func ToVectorContext[T any](ctx context.Context, c <-chan T) (vector.Vector[T], error) {
+	return ToSliceContext(ctx, c)
+}
+
+// ToSet returns a set with the values received from c until it is closed
+func ToSet[T comparable](c <-chan T) set.Set[T] {
+	s := set.Make[T]()
+	for v := range c {
+		s.Add(v)
+	}
+	return s
+}
+
+// ToSetContext is like ToSliceContext but returns a set
+func ToSetContext[T comparable](ctx context.Context, c <-chan T) (set.Set[T], error) {
+	s := set.Make[T]()
+	err := collectContext(ctx, c, s.Add)
+	return s, err
+}
+
+// ToList returns a list with the values received from c until it is closed, in order
+func ToList[T any](c <-chan T) *forward_list.ForwardList[T] {
+	l := forward_list.New[T]()
+	for v := range c {
+		l.PushBack(v)
+	}
+	return l
+}
+
+// ToListContext is like ToSliceContext but returns a list
+func ToListContext[T any](ctx context.Context, c <-chan T) (*forward_list.ForwardList[T], error) {
+	l := forward_list.New[T]()
+	err := collectContext(ctx, c, func(v T) { l.PushBack(v) })
+	return l, err
+}
+
+// FromSlice returns a closed channel buffered with the elements of xs.
+// It does not start a goroutine, so it never leaks even if the channel is not drained.
+func FromSlice[T any](xs []T) <-chan T {
+	r := make(chan T, len(xs))
+	for _, x := range xs {
+		r <- x
+	}
+	close(r)
+	return r
+}
+
+// FromSliceContext returns an unbuffered channel emitting the elements of xs.
+// The returned channel is closed after the last element or when ctx is done.
+// xs must not be modified until then.
+func FromSliceContext[T any](ctx context.Context, xs []T) <-chan T {
+	r := make(chan T)
+	go func() {
+		defer close(r)
+		for _, x := range xs {
+			if !send(ctx, r, x) {
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// FromVector is like FromSlice for a vector
+func FromVector[T any](vec vector.Vector[T]) <-chan T {
+	return FromSlice(vec)
+}
+
+// FromVectorContext is like FromSliceContext for a vector
+func FromVectorContext[T any](ctx context.Context, vec vector.Vector[T]) <-chan T {
+	return FromSliceContext(ctx, vec)
+}
+
+// FromSet is like FromSlice for a set, the elements are emitted in unspecified order
+func FromSet[T comparable](s set.Set[T]) <-chan T {
+	r := make(chan T, s.Len())
+	s.Do(func(v T) { r <- v })
+	close(r)
+	return r
+}
+
+// FromSetContext is like FromSliceContext for a set, the elements are emitted in unspecified order.
+func FromSetContext[T comparable](ctx context.Context, s set.Set[T]) <-chan T {
+	return FromSeq(ctx, s.Values())
+}
+
+// FromList is like FromSlice for a list
+func FromList[T any](l *forward_list.ForwardList[T]) <-chan T {
+	r := make(chan T, l.Len())
+	l.Do(func(v T) { r <- v })
+	close(r)
+	return r
+}
+
+// FromListContext is like FromSliceContext for a list
+func FromListContext[T any](ctx context.Context, l *forward_list.ForwardList[T]) <-chan T {
+	return FromSeq(ctx, l.Values())
+}
+
+// FromSeq returns an unbuffered channel emitting the values of seq.
+// The returned channel is closed after the last value or when ctx is done,
+// in which case the iteration over seq is stopped.
+func FromSeq[T any](ctx context.Context, seq iterator.Seq[T]) <-chan T {
+	r := make(chan T)
+	go func() {
+		defer close(r)
+		for v := range seq {
+			if !send(ctx, r, v) {
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// ToSeq returns an iterator over the values received from c until it is closed.
+// Stopping the iteration early leaves the remaining values in c.
+func ToSeq[T any](c <-chan T) iterator.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range c {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ToSeqContext is like ToSeq but the iteration also stops when ctx is done
+func ToSeqContext[T any](ctx context.Context, c <-chan T) iterator.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case v, ok := <-c:
+				if !ok || !yield(v) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// collectContext calls fn for each value received from c until it is closed or ctx is done
+func collectContext[T any](ctx context.Context, c <-chan T, fn func(T)) error {
+	for {
+		select {
+		case v, ok := <-c:
+			if !ok {
+				return nil
+			}
+			fn(v)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
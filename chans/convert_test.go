@@ -0,0 +1,153 @@
+package chans
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+	"github.com/redouan-rhazouani/goboost/container/forward_list"
+	"github.com/redouan-rhazouani/goboost/container/set"
+	"github.com/redouan-rhazouani/goboost/container/vector"
+	"github.com/redouan-rhazouani/goboost/iterator"
+)
+
+func TestToContainers(t *testing.T) {
+	xs := []int{3, 1, 2, 1}
+	if got := ToSlice(FromSlice(xs)); !equal(got, xs) {
+		t.Errorf("ToSlice got=%v want=%v", got, xs)
+	}
+	if got := ToVector(FromSlice(xs)); !vector.Equal(got, xs) {
+		t.Errorf("ToVector got=%v want=%v", got, xs)
+	}
+	if got, want := ToSet(FromSlice(xs)), set.FromSlice(xs); !got.Equal(want) {
+		t.Errorf("ToSet got=%v want=%v", got, want)
+	}
+	l := ToList(FromSlice(xs))
+	if got := ToSlice(FromList(l)); !equal(got, xs) {
+		t.Errorf("ToList got=%v want=%v", got, xs)
+	}
+	if got := ToSlice(FromSlice[int](nil)); len(got) != 0 {
+		t.Errorf("ToSlice of an empty channel got=%v want=[]", got)
+	}
+}
+
+func TestFromContainers(t *testing.T) {
+	ctx := context.Background()
+	xs := []int{3, 1, 2}
+	vec := vector.Vector[int](xs)
+	l := forward_list.New[int]()
+	for _, x := range xs {
+		l.PushBack(x)
+	}
+	s := set.FromSlice(xs)
+	tests := []struct {
+		name    string
+		c       <-chan int
+		ordered bool
+	}{
+		{"FromSlice", FromSlice(xs), true},
+		{"FromSliceContext", FromSliceContext(ctx, xs), true},
+		{"FromVector", FromVector(vec), true},
+		{"FromVectorContext", FromVectorContext(ctx, vec), true},
+		{"FromList", FromList(l), true},
+		{"FromListContext", FromListContext(ctx, l), true},
+		{"FromSet", FromSet(s), false},
+		{"FromSetContext", FromSetContext(ctx, s), false},
+	}
+	for _, tc := range tests {
+		got := ToSlice(tc.c)
+		want := append([]int(nil), xs...)
+		if !tc.ordered {
+			sort.Ints(got)
+			sort.Ints(want)
+		}
+		if !equal(got, want) {
+			t.Errorf("%s got=%v want=%v", tc.name, got, want)
+		}
+	}
+}
+
+func TestToContainersContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan int, 2)
+	c <- 1
+	c <- 2
+	go func() {
+		for len(c) > 0 {
+			runtime.Gosched()
+		}
+		cancel() // c is never closed
+	}()
+	got, err := ToSliceContext(ctx, c)
+	if !errors.Is(err, context.Canceled) || !equal(got, []int{1, 2}) {
+		t.Errorf("ToSliceContext got=(%v, %v) want=([1 2], %v)", got, err, context.Canceled)
+	}
+	if _, err := ToVectorContext(ctx, c); !errors.Is(err, context.Canceled) {
+		t.Errorf("ToVectorContext got=%v want=%v", err, context.Canceled)
+	}
+	if _, err := ToSetContext(ctx, c); !errors.Is(err, context.Canceled) {
+		t.Errorf("ToSetContext got=%v want=%v", err, context.Canceled)
+	}
+	if _, err := ToListContext(ctx, c); !errors.Is(err, context.Canceled) {
+		t.Errorf("ToListContext got=%v want=%v", err, context.Canceled)
+	}
+	ctx = context.Background()
+	s, err := ToSetContext(ctx, FromSlice([]int{1, 1, 2}))
+	if err != nil || !s.Equal(set.FromSlice([]int{1, 2})) {
+		t.Errorf("ToSetContext got=(%v, %v) want=([1 2], nil)", s, err)
+	}
+	l, err := ToListContext(ctx, FromSlice([]int{1, 2}))
+	if err != nil || l.Len() != 2 {
+		t.Errorf("ToListContext got=(%v, %v) want a list of 2 elements", l, err)
+	}
+}
+
+func TestFromContainersContextLeaks(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	xs := []int{1, 2, 3}
+	l := forward_list.New[int]()
+	l.PushBack(1)
+	l.PushBack(2)
+	cs := []<-chan int{
+		FromSliceContext(ctx, xs),
+		FromVectorContext(ctx, xs),
+		FromSetContext(ctx, set.FromSlice(xs)),
+		FromListContext(ctx, l),
+		FromSeq(ctx, iterator.Of(1, 2, 3)),
+	}
+	for _, c := range cs {
+		<-c // partially consumed
+	}
+	cancel()
+	check()
+}
+
+func TestSeq(t *testing.T) {
+	ctx := context.Background()
+	if got, want := chanstest.Collect(t, FromSeq(ctx, iterator.Of(1, 2, 3)), timeout), []int{1, 2, 3}; !equal(got, want) {
+		t.Errorf("FromSeq got=%v want=%v", got, want)
+	}
+	var got []int
+	c := FromSlice([]int{1, 2, 3})
+	for v := range ToSeq(c) {
+		got = append(got, v)
+		if v == 2 {
+			break
+		}
+	}
+	if want := []int{1, 2}; !equal(got, want) {
+		t.Errorf("ToSeq got=%v want=%v", got, want)
+	}
+	if v := <-c; v != 3 {
+		t.Errorf("ToSeq must leave the remaining values, got=%d want=3", v)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	for v := range ToSeqContext(ctx, make(chan int)) {
+		t.Errorf("ToSeqContext got=%d after ctx is done", v)
+	}
+}
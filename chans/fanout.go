@@ -0,0 +1,119 @@
+package chans
+
+import "context"
+
+// Overflow is the policy applied when a consumer is not ready to receive a value
+type Overflow int
+
+const (
+	// Block waits until the consumer is ready
+	Block Overflow = iota
+	// DropNewest discards the value that does not fit
+	DropNewest
+	// DropOldest discards the oldest buffered value to make room for the new one.
+	// Without a buffer it behaves like DropNewest.
+	DropOldest
+)
+
+// Broadcast sends every value of in to each of n returned channels.
+//
+// Each output is buffered with size values. A consumer that is not ready is
+// handled according to overflow: Block waits for it, holding back all other
+// consumers, while DropNewest and DropOldest discard values for it only.
+// All outputs are closed when in is closed or ctx is done.
+func Broadcast[T any](ctx context.Context, in <-chan T, n, size int, overflow Overflow) []<-chan T {
+	return fanOut(ctx, in, n, size, func(v T, outs []chan T) bool {
+		for _, c := range outs {
+			if !offer(ctx, c, v, overflow) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// RoundRobin spreads the values of in over n returned channels,
+// sending the i-th value to the channel i%n.
+// All outputs are closed when in is closed or ctx is done.
+func RoundRobin[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	i := 0
+	return fanOut(ctx, in, n, 0, func(v T, outs []chan T) bool {
+		c := outs[i]
+		i = (i + 1) % len(outs)
+		return send(ctx, c, v)
+	})
+}
+
+// Partition routes each value v of in to the returned channel key(v)%n,
+// so equal keys always reach the same consumer.
+// A typical key function hashes a field of v.
+// All outputs are closed when in is closed or ctx is done.
+func Partition[T any](ctx context.Context, in <-chan T, n int, key func(T) int) []<-chan T {
+	return fanOut(ctx, in, n, 0, func(v T, outs []chan T) bool {
+		i := key(v) % len(outs)
+		if i < 0 {
+			i += len(outs)
+		}
+		return send(ctx, outs[i], v)
+	})
+}
+
+// fanOut creates n channels buffered with size values and calls fn for
+// each value of in until fn returns false, in is closed or ctx is done.
+// It returns nil if n < 1.
+func fanOut[T any](ctx context.Context, in <-chan T, n, size int, fn func(v T, outs []chan T) bool) []<-chan T {
+	if n < 1 {
+		return nil
+	}
+	if size < 0 {
+		size = 0
+	}
+	outs := make([]chan T, n)
+	rs := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T, size)
+		rs[i] = outs[i]
+	}
+	go func() {
+		defer func() {
+			for _, c := range outs {
+				close(c)
+			}
+		}()
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok || !fn(v, outs) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return rs
+}
+
+// offer sends v on c applying the overflow policy when c is not ready.
+// It reports false if ctx is done.
+func offer[T any](ctx context.Context, c chan T, v T, overflow Overflow) bool {
+	if overflow == Block {
+		return send(ctx, c, v)
+	}
+	for {
+		select {
+		case c <- v:
+			return true
+		case <-ctx.Done():
+			return false
+		default:
+		}
+		if overflow == DropNewest || cap(c) == 0 {
+			return true
+		}
+		select {
+		case <-c: // make room, the consumer may win the race for it
+		default:
+		}
+	}
+}
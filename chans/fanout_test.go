@@ -0,0 +1,123 @@
+package chans
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+)
+
+// collectAll collects the values of all cs concurrently
+func collectAll[T any](t testing.TB, cs []<-chan T) [][]T {
+	rs := make([][]T, len(cs))
+	var wg sync.WaitGroup
+	wg.Add(len(cs))
+	for i, c := range cs {
+		go func(i int, c <-chan T) {
+			defer wg.Done()
+			rs[i] = chanstest.Collect(t, c, timeout)
+		}(i, c)
+	}
+	wg.Wait()
+	return rs
+}
+
+func TestBroadcast(t *testing.T) {
+	ctx := context.Background()
+	xs := []int{1, 2, 3, 4, 5}
+	for _, size := range []int{-1, 0, 2} {
+		for i, got := range collectAll(t, Broadcast(ctx, emit(xs...), 3, size, Block)) {
+			if !equal(got, xs) {
+				t.Errorf("Broadcast(size=%d) output %d got=%v want=%v", size, i, got, xs)
+			}
+		}
+	}
+	if cs := Broadcast(ctx, emit(xs...), 0, 0, Block); cs != nil {
+		t.Errorf("Broadcast(n=0) got=%v want nil", cs)
+	}
+}
+
+func TestBroadcastDrop(t *testing.T) {
+	ctx := context.Background()
+	xs := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		overflow Overflow
+		slow     []int
+	}{
+		{DropNewest, []int{1, 2}},
+		{DropOldest, []int{4, 5}},
+	}
+	for _, tc := range tests {
+		in := make(chan int)
+		cs := Broadcast(ctx, in, 2, 2, tc.overflow)
+		// cs[0] receives each value before the next one is sent,
+		// cs[1] is not read until in is closed
+		for _, x := range xs {
+			in <- x
+			if v := <-cs[0]; v != x {
+				t.Errorf("overflow=%d fast consumer got=%v want=%v", tc.overflow, v, x)
+			}
+		}
+		close(in)
+		if slow := chanstest.Collect(t, cs[1], timeout); !equal(slow, tc.slow) {
+			t.Errorf("overflow=%d slow consumer got=%v want=%v", tc.overflow, slow, tc.slow)
+		}
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	got := collectAll(t, RoundRobin(ctx, emit(0, 1, 2, 3, 4, 5, 6), 3))
+	want := [][]int{{0, 3, 6}, {1, 4}, {2, 5}}
+	for i := range want {
+		if !equal(got[i], want[i]) {
+			t.Errorf("output %d got=%v want=%v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPartition(t *testing.T) {
+	ctx := context.Background()
+	got := collectAll(t, Partition(ctx, emit(-2, -1, 0, 1, 2, 3, 4), 3, func(v int) int { return v }))
+	want := [][]int{{0, 3}, {-2, 1, 4}, {-1, 2}}
+	for i := range want {
+		if !equal(got[i], want[i]) {
+			t.Errorf("output %d got=%v want=%v", i, got[i], want[i])
+		}
+	}
+	hash := func(s string) int {
+		h := fnv.New32a()
+		h.Write([]byte(s))
+		return int(h.Sum32() & math.MaxInt32) // non-negative on 32-bit platforms
+	}
+	words := []string{"a", "b", "a", "c", "b", "a"}
+	for i, part := range collectAll(t, Partition(ctx, emit(words...), 4, hash)) {
+		for _, w := range part {
+			if hash(w)%4 != i {
+				t.Errorf("%q routed to output %d want %d", w, i, hash(w)%4)
+			}
+		}
+	}
+}
+
+func TestFanOutCancel(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	in := RepeatContext(ctx, 1, 100)
+	outs := [][]<-chan int{
+		Broadcast(ctx, in, 2, 0, Block),
+		RoundRobin(ctx, in, 2),
+		Partition(ctx, in, 2, func(v int) int { return v }),
+	}
+	cancel()
+	check()
+	for _, cs := range outs {
+		for _, c := range cs {
+			for range c {
+			}
+		}
+	}
+}
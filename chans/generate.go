@@ -0,0 +1,115 @@
+package chans
+
+import (
+	"context"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+// Number is a constraint that permits any integer or floating-point type
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Range emits start, start+step, start+2*step, ... up to but not including end.
+// A negative step counts down. If step is zero, the returned channel is closed immediately.
+// The returned channel is closed after the last value or when ctx is done.
+func Range[T Number](ctx context.Context, start, end, step T) <-chan T {
+	var zero T
+	if step == zero {
+		return closed[T]()
+	}
+	done := false
+	return Generate(ctx, func() (T, bool) {
+		v := start
+		if done || step > zero && v >= end || step < zero && v <= end {
+			return zero, false
+		}
+		start += step
+		done = step > zero && start < v || step < zero && start > v // overflow
+		return v, true
+	})
+}
+
+// Generate emits the values returned by fn until it returns false.
+// fn may keep state between calls, it is never called concurrently
+// and not called anymore once ctx is done.
+// The returned channel is closed when fn returns false or ctx is done.
+func Generate[T any](ctx context.Context, fn func() (T, bool)) <-chan T {
+	r := make(chan T)
+	go func() {
+		defer close(r)
+		for ctx.Err() == nil {
+			v, ok := fn()
+			if !ok || !send(ctx, r, v) {
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// Iterate emits the infinite sequence seed, f(seed), f(f(seed)), ...
+// The returned channel is closed when ctx is done.
+func Iterate[T any](ctx context.Context, seed T, f func(T) T) <-chan T {
+	v, started := seed, false
+	return Generate(ctx, func() (T, bool) {
+		if started {
+			v = f(v)
+		}
+		started = true
+		return v, true
+	})
+}
+
+// Cycle emits values over and over again.
+// If values is empty, the returned channel is closed immediately,
+// otherwise it is closed when ctx is done.
+func Cycle[T any](ctx context.Context, values ...T) <-chan T {
+	if len(values) == 0 {
+		return closed[T]()
+	}
+	i := 0
+	return Generate(ctx, func() (T, bool) {
+		v := values[i]
+		i = (i + 1) % len(values)
+		return v, true
+	})
+}
+
+// RepeatForever emits v until ctx is done.
+// Unlike Repeat the returned channel is unbuffered.
+func RepeatForever[T any](ctx context.Context, v T) <-chan T {
+	return Generate(ctx, func() (T, bool) { return v, true })
+}
+
+// Tick emits the current time every period d.
+// Unlike time.Tick the ticker is stopped and the returned channel is closed when ctx is done.
+// Ticks are dropped for slow consumers.
+// The time is read from the clock carried by ctx, see clock.From.
+// It panics if d <= 0.
+func Tick(ctx context.Context, d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		panic("chans: tick period must be positive")
+	}
+	r := make(chan time.Time)
+	ticker := clock.From(ctx).NewTicker(d)
+	go func() {
+		defer close(r)
+		defer ticker.Stop()
+		for {
+			select {
+			case t := <-ticker.C():
+				if !send(ctx, r, t) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return r
+}
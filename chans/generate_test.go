@@ -0,0 +1,114 @@
+package chans
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+func TestRange(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		start, end, step int
+		want             []int
+	}{
+		{0, 5, 1, []int{0, 1, 2, 3, 4}},
+		{0, 5, 2, []int{0, 2, 4}},
+		{5, 0, -2, []int{5, 3, 1}},
+		{0, 0, 1, []int{}},
+		{0, 5, -1, []int{}},
+		{0, 5, 0, []int{}},
+	}
+	for _, tc := range tests {
+		if got := chanstest.Collect(t, Range(ctx, tc.start, tc.end, tc.step), timeout); !equal(got, tc.want) {
+			t.Errorf("Range(%d, %d, %d) got=%v want=%v", tc.start, tc.end, tc.step, got, tc.want)
+		}
+	}
+	if got, want := chanstest.Collect(t, Range(ctx, 0, 1, 0.25), timeout), []float64{0, 0.25, 0.5, 0.75}; !equal(got, want) {
+		t.Errorf("Range(0, 1, 0.25) got=%v want=%v", got, want)
+	}
+	if got, want := chanstest.Collect(t, Range[uint8](ctx, 240, 255, 10), timeout), []uint8{240, 250}; !equal(got, want) {
+		t.Errorf("Range[uint8](240, 255, 10) got=%v want=%v", got, want)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	a, b := 0, 1
+	fib := Generate(ctx, func() (int, bool) {
+		v := a
+		a, b = b, a+b
+		return v, v < 20
+	})
+	if got, want := chanstest.Collect(t, fib, timeout), []int{0, 1, 1, 2, 3, 5, 8, 13}; !equal(got, want) {
+		t.Errorf("Generate got=%v want=%v", got, want)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	double := func(v int) int { return 2 * v }
+	if got, want := chanstest.Collect(t, Take(ctx, Iterate(ctx, 1, double), 5), timeout), []int{1, 2, 4, 8, 16}; !equal(got, want) {
+		t.Errorf("Iterate got=%v want=%v", got, want)
+	}
+	if got, want := chanstest.Collect(t, Take(ctx, Cycle(ctx, 1, 2, 3), 7), timeout), []int{1, 2, 3, 1, 2, 3, 1}; !equal(got, want) {
+		t.Errorf("Cycle got=%v want=%v", got, want)
+	}
+	if got := chanstest.Collect(t, Cycle[int](ctx), timeout); len(got) != 0 {
+		t.Errorf("Cycle() got=%v want=[]", got)
+	}
+	if got, want := chanstest.Collect(t, Take(ctx, RepeatForever(ctx, 7), 3), timeout), []int{7, 7, 7}; !equal(got, want) {
+		t.Errorf("RepeatForever got=%v want=%v", got, want)
+	}
+}
+
+func TestTick(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx, cancel := context.WithCancel(clock.With(context.Background(), clk))
+	c := Tick(ctx, time.Second)
+	start := clk.Now()
+	for i := 1; i <= 3; i++ {
+		clk.Advance(time.Second)
+		if got, want := <-c, start.Add(time.Duration(i)*time.Second); !got.Equal(want) {
+			t.Errorf("tick %d got=%v want=%v", i, got, want)
+		}
+	}
+	cancel()
+	for range c {
+	}
+	if n := clk.Active(); n != 0 {
+		t.Errorf("ticker must be stopped, active timers got=%d", n)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Tick must panic for a zero period")
+		}
+	}()
+	Tick(ctx, 0)
+}
+
+func TestGenerateCancel(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cs := []<-chan int{
+		Range(ctx, 0, 1000, 1),
+		Generate(ctx, func() (int, bool) { return 1, true }),
+		Iterate(ctx, 0, func(v int) int { return v + 1 }),
+		Cycle(ctx, 1, 2),
+		RepeatForever(ctx, 1),
+	}
+	for _, c := range cs {
+		<-c
+	}
+	cancel()
+	check()
+	calls := 0
+	gen := Generate(ctx, func() (int, bool) { calls++; return 1, true })
+	iter := Iterate(ctx, 0, func(v int) int { calls++; return v + 1 })
+	chanstest.AssertClosed(t, gen, timeout)
+	chanstest.AssertClosed(t, iter, timeout)
+	if calls != 0 {
+		t.Errorf("generators of a done context must not be called, calls got=%d want=0", calls)
+	}
+}
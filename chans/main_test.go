@@ -0,0 +1,15 @@
+package chans
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+)
+
+// timeout bounds the wait for values in tests
+const timeout = time.Second
+
+func TestMain(m *testing.M) {
+	chanstest.VerifyTestMain(m)
+}
@@ -1,6 +1,19 @@
 // Package chans implements various algorithms on channels
+//
+// # Cancellation
+//
+// Every function that blocks or starts a goroutine can be cancelled through a
+// context.Context. Functions that were written before context support keep
+// their signature and have a counterpart with the Context suffix taking the
+// context as its first argument (Merge and MergeContext); all other functions
+// take the context as their first argument directly.
+// Once the context is done, the goroutine stops and closes its output channel.
+// Values that were not yet received from the inputs are left in place,
+// so producers upstream should share the same context.
 package chans
 
+import "context"
+
 // Drain drains a channel
 func Drain[T any](c <-chan T) {
 	for range c {
@@ -33,7 +46,19 @@ func Merge[T any](c1, c2 <-chan T) <-chan T {
 	return r
 }
 
+// MergeN merges any number of channels into a single one.
+//
+// All inputs are served by a single goroutine. When several inputs are ready
+// one of them is chosen uniformly at random, so no input is starved under load.
+// The returned channel is closed after every input has been closed.
+func MergeN[T any](cs ...<-chan T) <-chan T {
+	return MergeNContext(context.Background(), cs...)
+}
+
 // Repeat value v n times
+//
+// The returned channel is buffered with n values.
+// Use RepeatContext or RepeatForever to avoid the allocation for large n.
 func Repeat[T any](v T, n int) <-chan T {
 	r := make(chan T, n)
 	go func(c <-chan T, v T, n int) {
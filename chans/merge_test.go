@@ -1,7 +1,11 @@
 package chans
 
 import (
+	"fmt"
+	"runtime"
 	"testing"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
 )
 
 func TestMerge(t *testing.T) {
@@ -17,7 +21,97 @@ func TestMerge(t *testing.T) {
 	}
 	c1 = Repeat(1, n)
 	Drain(c1)
-	if _, ok := <-c1; ok {
-		t.Errorf("channel must be closed after being drained")
+	chanstest.AssertClosed(t, c1, timeout)
+}
+
+func TestMergeN(t *testing.T) {
+	n := 20
+	if _, ok := <-MergeN[int](); ok {
+		t.Errorf("MergeN() must return a closed channel")
+	}
+	for _, m := range []int{1, 2, 3, 10} {
+		cs := make([]<-chan int, m)
+		for i := range cs {
+			cs[i] = Repeat(i, n)
+		}
+		counts := make([]int, m)
+		for v := range MergeN(cs...) {
+			counts[v]++
+		}
+		for i, c := range counts {
+			if c != n {
+				t.Errorf("MergeN(%d channels): input %d got=%d elements want=%d", m, i, c, n)
+			}
+		}
+	}
+	// nil channels are ignored
+	a := make([]int, 0, n)
+	for v := range MergeN(nil, Repeat(1, n), nil) {
+		a = append(a, v)
+	}
+	if m := len(a); m != n {
+		t.Errorf("Number of elements got=%d want=%d", m, n)
+	}
+	// nil interface values are forwarded
+	var err error
+	for v := range MergeN(Repeat(err, 1)) {
+		if v != nil {
+			t.Errorf("got=%v want nil", v)
+		}
+	}
+}
+
+func TestMergeNFairness(t *testing.T) {
+	inputs, n, sample := 10, 1000, 1000
+	cs := make([]<-chan int, inputs)
+	for i := range cs {
+		cs[i] = Repeat(i, n)
+		for len(cs[i]) < n { // wait until every input is always ready
+			runtime.Gosched()
+		}
+	}
+	c := MergeN(cs...)
+	counts := make([]int, inputs)
+	for i := 0; i < sample; i++ {
+		counts[<-c]++
+	}
+	Drain(c)
+	// each input is expected sample/inputs times; a starved input would be far below
+	for i, m := range counts {
+		if want := sample / inputs / 4; m < want {
+			t.Errorf("input %d served %d times out of %d, want at least %d", i, m, sample, want)
+		}
+	}
+}
+
+// nestedMerge merges cs by building a tree of Merge calls
+func nestedMerge[T any](cs ...<-chan T) <-chan T {
+	switch len(cs) {
+	case 0:
+		return MergeN[T]()
+	case 1:
+		return cs[0]
+	}
+	return Merge(nestedMerge(cs[:len(cs)/2]...), nestedMerge(cs[len(cs)/2:]...))
+}
+
+func benchmarkMerge(b *testing.B, inputs int, merge func(...<-chan int) <-chan int) {
+	n := b.N/inputs + 1
+	cs := make([]<-chan int, inputs)
+	for i := range cs {
+		cs[i] = Repeat(i, n)
+	}
+	b.ResetTimer()
+	Drain(merge(cs...))
+}
+
+func BenchmarkMergeN(b *testing.B) {
+	for _, inputs := range []int{2, 8, 32} {
+		b.Run(fmt.Sprintf("MergeN/%d", inputs), func(b *testing.B) {
+			benchmarkMerge(b, inputs, MergeN[int])
+		})
+		b.Run(fmt.Sprintf("Nested/%d", inputs), func(b *testing.B) {
+			benchmarkMerge(b, inputs, nestedMerge[int])
+		})
 	}
 }
@@ -0,0 +1,71 @@
+package chans
+
+import (
+	"context"
+
+	"github.com/redouan-rhazouani/goboost/concurrency"
+)
+
+// OrderedParallelMap is like ParallelMap but emits results in input order.
+//
+// fn runs on up to workers values at once. Results that complete early wait in
+// a reorder window holding at most workers values, so memory stays bounded
+// while a slow value holds back the ones after it.
+// The returned channel is closed when in is closed or ctx is done.
+func OrderedParallelMap[T, U any](ctx context.Context, in <-chan T, workers int, fn func(T) U) <-chan U {
+	return orderedStage(ctx, in, workers, func(_ int, v T) U { return fn(v) })
+}
+
+// OrderedParallelMapResult is like OrderedParallelMap for functions that can fail.
+// Each result is reported as a concurrency.TaskResult whose Index is the position of
+// the value in the input, a failure does not stop the stage.
+func OrderedParallelMapResult[T, U any](ctx context.Context, in <-chan T, workers int, fn func(context.Context, T) (U, error)) <-chan concurrency.TaskResult[U] {
+	return orderedStage(ctx, in, workers, func(i int, v T) concurrency.TaskResult[U] {
+		res, err := fn(ctx, v)
+		return concurrency.TaskResult[U]{Result: res, Err: err, Index: i}
+	})
+}
+
+// orderedStage applies fn on up to workers values of in concurrently
+// and emits the results in input order. fn receives the position of the value in the input.
+func orderedStage[T, U any](ctx context.Context, in <-chan T, workers int, fn func(int, T) U) <-chan U {
+	r := make(chan U)
+	if workers < 1 {
+		workers = 1
+	}
+	// window holds the pending results in input order.
+	// Together with the slot held by the emitter, at most workers values are in flight.
+	window := make(chan chan U, workers-1)
+	go func() {
+		defer close(window)
+		for i := 0; ; i++ {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				slot := make(chan U, 1)
+				if !send(ctx, window, slot) {
+					return
+				}
+				go func(i int, v T) { slot <- fn(i, v) }(i, v)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		defer close(r)
+		for slot := range window {
+			select {
+			case u := <-slot:
+				if !send(ctx, r, u) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return r
+}
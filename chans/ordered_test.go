@@ -0,0 +1,84 @@
+package chans
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+)
+
+func TestOrderedParallelMap(t *testing.T) {
+	ctx := context.Background()
+	n := 50
+	xs := make([]int, n)
+	for i := range xs {
+		xs[i] = i
+	}
+	for _, workers := range []int{0, 1, 3, 8} {
+		var active, peak int32
+		fn := func(v int) int {
+			m := atomic.AddInt32(&active, 1)
+			defer atomic.AddInt32(&active, -1)
+			for p := atomic.LoadInt32(&peak); m > p && !atomic.CompareAndSwapInt32(&peak, p, m); p = atomic.LoadInt32(&peak) {
+			}
+			time.Sleep(time.Duration(rand.Intn(200)) * time.Microsecond)
+			return -v
+		}
+		got := chanstest.Collect(t, OrderedParallelMap(ctx, emit(xs...), workers, fn), timeout)
+		if len(got) != n {
+			t.Fatalf("workers=%d len got=%d want=%d", workers, len(got), n)
+		}
+		for i, v := range got {
+			if v != -i {
+				t.Fatalf("workers=%d got[%d]=%d want=%d", workers, i, v, -i)
+			}
+		}
+		limit := int32(workers)
+		if limit < 1 {
+			limit = 1
+		}
+		if peak > limit {
+			t.Errorf("workers=%d concurrent calls got=%d want<=%d", workers, peak, limit)
+		}
+	}
+}
+
+func TestOrderedParallelMapResult(t *testing.T) {
+	ctx := context.Background()
+	errOdd := errors.New("odd")
+	fn := func(ctx context.Context, v int) (int, error) {
+		if v%2 == 1 {
+			return 0, errOdd
+		}
+		return v, nil
+	}
+	i := 0
+	for r := range OrderedParallelMapResult(ctx, emit(0, 1, 2, 3, 4), 2, fn) {
+		if r.Index != i || i%2 == 1 && !errors.Is(r.Err, errOdd) || i%2 == 0 && (r.Err != nil || r.Result != i) {
+			t.Errorf("result %d got=%v", i, r)
+		}
+		i++
+	}
+	if i != 5 {
+		t.Errorf("number of results got=%d want=5", i)
+	}
+}
+
+func TestOrderedParallelMapCancel(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	block := make(chan struct{})
+	c := OrderedParallelMap(ctx, RepeatContext(ctx, 1, 100), 4, func(v int) int {
+		<-block
+		return v
+	})
+	cancel()
+	close(block)
+	check()
+	for range c {
+	}
+}
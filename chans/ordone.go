@@ -0,0 +1,133 @@
+package chans
+
+import (
+	"context"
+	"reflect"
+)
+
+// OrDone forwards the values of c until c is closed or done is closed.
+// It lets a consumer range over a channel and still stop on a done signal.
+// The returned channel is closed when c or done is closed.
+func OrDone[T any](done <-chan struct{}, c <-chan T) <-chan T {
+	r := make(chan T)
+	go func() {
+		defer close(r)
+		for {
+			select {
+			case v, ok := <-c:
+				if !ok {
+					return
+				}
+				select {
+				case r <- v:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// Or returns a channel that is closed as soon as any of dones is closed or receives a value.
+// With no channel Or returns nil, a channel that is never closed.
+// The goroutine waiting on dones stops only when one of them fires,
+// use OrContext to stop it otherwise.
+func Or(dones ...<-chan struct{}) <-chan struct{} {
+	switch len(dones) {
+	case 0:
+		return nil
+	case 1:
+		return dones[0]
+	}
+	r := make(chan struct{})
+	cases := make([]reflect.SelectCase, len(dones))
+	for i, c := range dones {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c)}
+	}
+	go func() {
+		defer close(r)
+		reflect.Select(cases)
+	}()
+	return r
+}
+
+// OrContext is like Or but the returned channel is also closed when ctx is done,
+// which stops the goroutine waiting on dones.
+func OrContext(ctx context.Context, dones ...<-chan struct{}) <-chan struct{} {
+	return Or(append(dones[:len(dones):len(dones)], ctx.Done())...)
+}
+
+// Bridge flattens a channel of channels: it forwards the values of each channel
+// received from cs, one channel after the other, preserving their order.
+// The returned channel is closed when cs and the last channel are closed or ctx is done.
+func Bridge[T any](ctx context.Context, cs <-chan (<-chan T)) <-chan T {
+	r := make(chan T)
+	go func() {
+		defer close(r)
+		for {
+			var c <-chan T
+			select {
+			case next, ok := <-cs:
+				if !ok {
+					return
+				}
+				c = next
+			case <-ctx.Done():
+				return
+			}
+			if err := forward(ctx, c, r); err != nil {
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// FirstOf returns the first value received from any of cs.
+// It returns ErrClosed if all of cs are closed without value
+// and ctx.Err() if ctx is done first.
+func FirstOf[T any](ctx context.Context, cs ...<-chan T) (T, error) {
+	var zero T
+	cases := make([]reflect.SelectCase, 1, len(cs)+1)
+	cases[0] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	for _, c := range cs {
+		if c != nil {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c)})
+		}
+	}
+	for len(cases) > 1 {
+		i, v, ok := reflect.Select(cases)
+		if i == 0 {
+			return zero, ctx.Err()
+		}
+		if ok {
+			x, _ := v.Interface().(T) // nil interface values yield T's zero value
+			return x, nil
+		}
+		n := len(cases) - 1
+		cases[i], cases[n] = cases[n], reflect.SelectCase{}
+		cases = cases[:n]
+	}
+	return zero, ErrClosed
+}
+
+// forward sends the values of c on r until c is closed.
+// It returns ctx.Err() if ctx is done first.
+func forward[T any](ctx context.Context, c <-chan T, r chan<- T) error {
+	for {
+		select {
+		case v, ok := <-c:
+			if !ok {
+				return nil
+			}
+			if !send(ctx, r, v) {
+				return ctx.Err()
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
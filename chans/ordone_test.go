@@ -0,0 +1,97 @@
+package chans
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+)
+
+func TestOrDone(t *testing.T) {
+	done := make(chan struct{})
+	if got := chanstest.Collect(t, OrDone(done, FromSlice([]int{1, 2})), timeout); !equal(got, []int{1, 2}) {
+		t.Errorf("OrDone got=%v want=[1 2]", got)
+	}
+	check := chanstest.LeakCheck(t)
+	c := OrDone(done, make(chan int)) // never closed
+	close(done)
+	for range c {
+	}
+	check()
+}
+
+func TestOr(t *testing.T) {
+	if Or() != nil {
+		t.Errorf("Or() must return nil")
+	}
+	a := make(chan struct{})
+	if Or(a) != (<-chan struct{})(a) {
+		t.Errorf("Or(a) must return a")
+	}
+	check := chanstest.LeakCheck(t)
+	b, c := make(chan struct{}), make(chan struct{})
+	done := Or(a, b, c)
+	chanstest.AssertNoValue(t, done)
+	close(b)
+	<-done
+	check()
+}
+
+func TestOrContext(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	a, b := make(chan struct{}), make(chan struct{})
+	done := OrContext(ctx, a, b)
+	chanstest.AssertNoValue(t, done)
+	cancel() // none of dones ever fires
+	if _, ok := chanstest.Receive(t, done, timeout); ok {
+		t.Errorf("OrContext must be closed once ctx is done")
+	}
+	check()
+	if _, ok := chanstest.Receive(t, OrContext(context.Background(), a, closed[struct{}]()), timeout); ok {
+		t.Errorf("OrContext must be closed once one of dones is")
+	}
+	check()
+}
+
+func TestBridge(t *testing.T) {
+	ctx := context.Background()
+	cs := make(chan (<-chan int), 3)
+	cs <- FromSlice([]int{1, 2})
+	cs <- FromSlice([]int{})
+	cs <- FromSlice([]int{3})
+	close(cs)
+	if got := chanstest.Collect(t, Bridge(ctx, cs), timeout); !equal(got, []int{1, 2, 3}) {
+		t.Errorf("Bridge got=%v want=[1 2 3]", got)
+	}
+	check := chanstest.LeakCheck(t)
+	ctx, cancel := context.WithCancel(ctx)
+	cs = make(chan (<-chan int), 1)
+	cs <- RepeatForever(ctx, 1)
+	r := Bridge(ctx, cs)
+	<-r
+	cancel()
+	for range r {
+	}
+	check()
+}
+
+func TestFirstOf(t *testing.T) {
+	ctx := context.Background()
+	slow := make(chan int)
+	if v, err := FirstOf(ctx, slow, FromSlice([]int{2}), nil); v != 2 || err != nil {
+		t.Errorf("FirstOf got=(%v, %v) want=(2, nil)", v, err)
+	}
+	if _, err := FirstOf(ctx, FromSlice([]int{}), closed[int]()); !errors.Is(err, ErrClosed) {
+		t.Errorf("FirstOf closed channels got=%v want=%v", err, ErrClosed)
+	}
+	if _, err := FirstOf[int](ctx); !errors.Is(err, ErrClosed) {
+		t.Errorf("FirstOf() got=%v want=%v", err, ErrClosed)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := FirstOf(ctx, slow); !errors.Is(err, context.Canceled) {
+		t.Errorf("FirstOf cancelled got=%v want=%v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,160 @@
+package chans
+
+import (
+	"context"
+	"sync"
+)
+
+// Map returns a channel with the result of applying fn to each value of in.
+// The returned channel is closed when in is closed or ctx is done.
+func Map[T, U any](ctx context.Context, in <-chan T, fn func(T) U) <-chan U {
+	return ParallelMap(ctx, in, 1, fn)
+}
+
+// ParallelMap is like Map but applies fn on up to workers values concurrently.
+// Results are emitted in completion order.
+func ParallelMap[T, U any](ctx context.Context, in <-chan T, workers int, fn func(T) U) <-chan U {
+	return stage(ctx, in, workers, func(v T, emit func(U) bool) bool {
+		return emit(fn(v))
+	})
+}
+
+// Filter returns a channel with the values of in satisfying pred.
+// The returned channel is closed when in is closed or ctx is done.
+func Filter[T any](ctx context.Context, in <-chan T, pred func(T) bool) <-chan T {
+	return ParallelFilter(ctx, in, 1, pred)
+}
+
+// ParallelFilter is like Filter but evaluates pred on up to workers values concurrently.
+// Values are emitted in completion order.
+func ParallelFilter[T any](ctx context.Context, in <-chan T, workers int, pred func(T) bool) <-chan T {
+	return stage(ctx, in, workers, func(v T, emit func(T) bool) bool {
+		return !pred(v) || emit(v)
+	})
+}
+
+// FlatMap returns a channel with the elements of the slices returned by fn
+// for each value of in.
+// The returned channel is closed when in is closed or ctx is done.
+func FlatMap[T, U any](ctx context.Context, in <-chan T, fn func(T) []U) <-chan U {
+	return ParallelFlatMap(ctx, in, 1, fn)
+}
+
+// ParallelFlatMap is like FlatMap but applies fn on up to workers values concurrently.
+// The elements of each slice are emitted contiguously, slices in completion order:
+// a worker waits while another one emits its slice.
+func ParallelFlatMap[T, U any](ctx context.Context, in <-chan T, workers int, fn func(T) []U) <-chan U {
+	var mu sync.Mutex // held while emitting a slice
+	return stage(ctx, in, workers, func(v T, emit func(U) bool) bool {
+		us := fn(v)
+		mu.Lock()
+		defer mu.Unlock()
+		for _, u := range us {
+			if !emit(u) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Scan returns a channel with the running fold of in:
+// each value v of in emits acc = fn(acc, v), starting with acc = init.
+// The returned channel is closed when in is closed or ctx is done.
+func Scan[T, U any](ctx context.Context, in <-chan T, init U, fn func(U, T) U) <-chan U {
+	acc := init
+	return stage(ctx, in, 1, func(v T, emit func(U) bool) bool {
+		acc = fn(acc, v)
+		return emit(acc)
+	})
+}
+
+// Take returns a channel with the first n values of in.
+// The returned channel is closed after n values, when in is closed or ctx is done.
+// The remaining values of in are not consumed.
+func Take[T any](ctx context.Context, in <-chan T, n int) <-chan T {
+	if n <= 0 {
+		return closed[T]()
+	}
+	return stage(ctx, in, 1, func(v T, emit func(T) bool) bool {
+		n--
+		return emit(v) && n > 0
+	})
+}
+
+// Skip returns a channel with the values of in except the first n.
+// The returned channel is closed when in is closed or ctx is done.
+func Skip[T any](ctx context.Context, in <-chan T, n int) <-chan T {
+	return stage(ctx, in, 1, func(v T, emit func(T) bool) bool {
+		if n > 0 {
+			n--
+			return true
+		}
+		return emit(v)
+	})
+}
+
+// TakeWhile returns a channel with the values of in as long as pred is satisfied.
+// The returned channel is closed at the first value not satisfying pred,
+// when in is closed or ctx is done.
+// The remaining values of in are not consumed.
+func TakeWhile[T any](ctx context.Context, in <-chan T, pred func(T) bool) <-chan T {
+	return stage(ctx, in, 1, func(v T, emit func(T) bool) bool {
+		return pred(v) && emit(v)
+	})
+}
+
+// DropWhile returns a channel with the values of in starting at the first
+// value that does not satisfy pred.
+// The returned channel is closed when in is closed or ctx is done.
+func DropWhile[T any](ctx context.Context, in <-chan T, pred func(T) bool) <-chan T {
+	dropping := true
+	return stage(ctx, in, 1, func(v T, emit func(T) bool) bool {
+		if dropping && pred(v) {
+			return true
+		}
+		dropping = false
+		return emit(v)
+	})
+}
+
+// stage starts workers goroutines applying fn to each value of in.
+// fn sends its results through emit and returns false to stop the stage.
+// emit reports false once ctx is done.
+// The returned channel is closed when all workers have stopped.
+func stage[T, U any](ctx context.Context, in <-chan T, workers int, fn func(v T, emit func(U) bool) bool) <-chan U {
+	r := make(chan U)
+	if workers < 1 {
+		workers = 1
+	}
+	emit := func(u U) bool { return send(ctx, r, u) }
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case v, ok := <-in:
+					if !ok || !fn(v, emit) {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(r)
+	}()
+	return r
+}
+
+// closed returns a closed channel
+func closed[T any]() <-chan T {
+	r := make(chan T)
+	close(r)
+	return r
+}
@@ -0,0 +1,128 @@
+package chans
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+)
+
+// emit returns a channel with the values of xs
+func emit[T any](xs ...T) <-chan T {
+	return FromSlice(xs)
+}
+
+func equal[T comparable](x, y []T) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPipeline(t *testing.T) {
+	ctx := context.Background()
+	even := func(v int) bool { return v%2 == 0 }
+	small := func(v int) bool { return v < 3 }
+	tests := []struct {
+		name string
+		c    <-chan int
+		want []int
+	}{
+		{"Map", Map(ctx, emit(1, 2, 3), func(v int) int { return v * v }), []int{1, 4, 9}},
+		{"MapEmpty", Map(ctx, emit[int](), func(v int) int { return v }), []int{}},
+		{"Filter", Filter(ctx, emit(1, 2, 3, 4), even), []int{2, 4}},
+		{"FlatMap", FlatMap(ctx, emit(1, 2, 3), func(v int) []int { return make([]int, v) }), []int{0, 0, 0, 0, 0, 0}},
+		{"Scan", Scan(ctx, emit(1, 2, 3, 4), 0, func(acc, v int) int { return acc + v }), []int{1, 3, 6, 10}},
+		{"Take", Take(ctx, emit(1, 2, 3, 4), 2), []int{1, 2}},
+		{"TakeZero", Take(ctx, emit(1, 2), 0), []int{}},
+		{"TakeMore", Take(ctx, emit(1, 2), 5), []int{1, 2}},
+		{"Skip", Skip(ctx, emit(1, 2, 3, 4), 3), []int{4}},
+		{"SkipMore", Skip(ctx, emit(1, 2), 3), []int{}},
+		{"TakeWhile", TakeWhile(ctx, emit(1, 2, 3, 1), small), []int{1, 2}},
+		{"DropWhile", DropWhile(ctx, emit(1, 2, 3, 1), small), []int{3, 1}},
+	}
+	for _, tc := range tests {
+		if got := chanstest.Collect(t, tc.c, timeout); !equal(got, tc.want) {
+			t.Errorf("%s got=%v want=%v", tc.name, got, tc.want)
+		}
+	}
+	strs := chanstest.Collect(t, Map(ctx, emit(1, 2), strconv.Itoa), timeout)
+	if !equal(strs, []string{"1", "2"}) {
+		t.Errorf("Map(strconv.Itoa) got=%v want=[1 2]", strs)
+	}
+}
+
+func TestParallelPipeline(t *testing.T) {
+	ctx := context.Background()
+	n := 100
+	in := func() <-chan int {
+		xs := make([]int, n)
+		for i := range xs {
+			xs[i] = i
+		}
+		return emit(xs...)
+	}
+	for _, workers := range []int{0, 1, 4, 16} {
+		got := chanstest.Collect(t, ParallelMap(ctx, in(), workers, func(v int) int { return 2 * v }), timeout)
+		sort.Ints(got)
+		for i, v := range got {
+			if v != 2*i {
+				t.Fatalf("ParallelMap(workers=%d) got[%d]=%d want=%d", workers, i, v, 2*i)
+			}
+		}
+		if len(got) != n {
+			t.Errorf("ParallelMap(workers=%d) len got=%d want=%d", workers, len(got), n)
+		}
+		got = chanstest.Collect(t, ParallelFilter(ctx, in(), workers, func(v int) bool { return v < 10 }), timeout)
+		if len(got) != 10 {
+			t.Errorf("ParallelFilter(workers=%d) len got=%d want=10", workers, len(got))
+		}
+		got = chanstest.Collect(t, ParallelFlatMap(ctx, in(), workers, func(v int) []int { return []int{v, v} }), timeout)
+		if len(got) != 2*n {
+			t.Errorf("ParallelFlatMap(workers=%d) len got=%d want=%d", workers, len(got), 2*n)
+		}
+
+	}
+}
+
+func TestParallelFlatMapContiguous(t *testing.T) {
+	ctx := context.Background()
+	c := ParallelFlatMap(ctx, Range(ctx, 0, 50, 1), 8, func(v int) []int {
+		return []int{v, v, v, v}
+	})
+	var got []int
+	for v := range c { // a slow consumer lets the workers compete
+		got = append(got, v)
+		time.Sleep(10 * time.Microsecond)
+	}
+	if len(got) != 200 {
+		t.Fatalf("len got=%d want=200", len(got))
+	}
+	for i := 0; i < len(got); i += 4 {
+		if s := got[i : i+4]; s[0] != s[1] || s[0] != s[2] || s[0] != s[3] {
+			t.Fatalf("slices must be contiguous, got[%d:%d]=%v", i, i+4, s)
+		}
+	}
+}
+
+func TestPipelineCancel(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	src := RepeatContext(ctx, 1, 1000)
+	c := Take(ctx, Scan(ctx, ParallelMap(ctx, src, 4, func(v int) int { return v }), 0, func(acc, v int) int { return acc + v }), 500)
+	if v := <-c; v != 1 {
+		t.Errorf("got=%d want=1", v)
+	}
+	cancel()
+	check()
+	for range c {
+	}
+}
@@ -0,0 +1,192 @@
+package chans
+
+import (
+	"context"
+	"sync"
+
+	"github.com/redouan-rhazouani/goboost/concurrency"
+)
+
+// Pipeline coordinates the stages of streams that can fail.
+//
+// The stages of a pipeline share its context. The first error reported by any
+// stage is recorded and cancels that context, which stops every other stage.
+// A Pipeline is safe for concurrent use.
+type Pipeline struct {
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	err    error
+}
+
+// NewPipeline returns a new pipeline and its context derived from ctx.
+// The context is cancelled on the first failure, by Stop or when ctx is done.
+// Collect and Drain stop the pipeline, otherwise call Stop once it is no longer used
+// to release its context.
+func NewPipeline(ctx context.Context) (*Pipeline, context.Context) {
+	pctx, cancel := context.WithCancel(ctx)
+	return &Pipeline{parent: ctx, ctx: pctx, cancel: cancel}, pctx
+}
+
+// Context returns the context of pipeline p
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Fail records err, if it is the first error, and cancels the pipeline.
+// A nil err has no effect.
+func (p *Pipeline) Fail(err error) {
+	if err == nil {
+		return
+	}
+	p.mu.Lock()
+	if p.err == nil {
+		p.err = err
+	}
+	p.mu.Unlock()
+	p.cancel()
+}
+
+// Stop cancels the pipeline without failure, the stages still running stop.
+// It is safe to call Stop more than once.
+func (p *Pipeline) Stop() {
+	p.cancel()
+}
+
+// Err returns the first error reported by a stage.
+// If no stage failed, it returns the error of the parent context if it is done.
+func (p *Pipeline) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	return p.parent.Err()
+}
+
+// Stream is a channel of values produced by a stage of a Pipeline
+type Stream[T any] struct {
+	p *Pipeline
+	c <-chan T
+}
+
+// Source starts fn in a goroutine as the first stage of a stream of pipeline p.
+// fn sends its values on out and must return once ctx is done.
+// A non-nil error returned by fn fails the pipeline.
+// out is closed when fn returns.
+func Source[T any](p *Pipeline, fn func(ctx context.Context, out chan<- T) error) Stream[T] {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		p.Fail(fn(p.ctx, out))
+	}()
+	return Stream[T]{p, out}
+}
+
+// From returns a stream of pipeline p forwarding the values of c,
+// so that the results of Repeat, Merge or any other function can join a pipeline.
+// The stream is closed when c is closed or the pipeline is cancelled.
+func From[T any](p *Pipeline, c <-chan T) Stream[T] {
+	return Source(p, func(ctx context.Context, out chan<- T) error {
+		forward(ctx, c, out) // a cancellation is not a failure of this stage
+		return nil
+	})
+}
+
+// FromResults returns a stream of pipeline p forwarding the results of c,
+// such as the channel returned by concurrency.WhenAll.
+// The first result with a non-nil error fails the pipeline.
+func FromResults[T any](p *Pipeline, c <-chan concurrency.TaskResult[T]) Stream[T] {
+	return Source(p, func(ctx context.Context, out chan<- T) error {
+		for {
+			select {
+			case r, ok := <-c:
+				if !ok {
+					return nil
+				}
+				if r.Err != nil {
+					return r.Err
+				}
+				if !send(ctx, out, r.Result) {
+					return nil
+				}
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	})
+}
+
+// Transform starts fn in a goroutine as a stage reading from stream s.
+// fn reads from in, sends its values on out and must return once ctx is done.
+// A non-nil error returned by fn fails the pipeline.
+// out is closed when fn returns.
+func Transform[T, U any](s Stream[T], fn func(ctx context.Context, in <-chan T, out chan<- U) error) Stream[U] {
+	return Source(s.p, func(ctx context.Context, out chan<- U) error {
+		return fn(ctx, s.c, out)
+	})
+}
+
+// Apply returns a stream with the output of op applied to stream s.
+// op is any function of this package that cannot fail,
+// for instance:
+//
+//	Apply(s, func(ctx context.Context, in <-chan int) <-chan int {
+//		return Take(ctx, in, 10)
+//	})
+func Apply[T, U any](s Stream[T], op func(ctx context.Context, in <-chan T) <-chan U) Stream[U] {
+	return From(s.p, op(s.p.ctx, s.c))
+}
+
+// TryMap returns a stream with the result of applying fn to each value of s.
+// The first error returned by fn fails the pipeline.
+func TryMap[T, U any](s Stream[T], fn func(context.Context, T) (U, error)) Stream[U] {
+	return Transform(s, func(ctx context.Context, in <-chan T, out chan<- U) error {
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return nil
+				}
+				u, err := fn(ctx, v)
+				if err != nil {
+					return err
+				}
+				if !send(ctx, out, u) {
+					return nil
+				}
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	})
+}
+
+// C returns the channel of stream s.
+// It is closed when the stage producing s stops.
+func (s Stream[T]) C() <-chan T {
+	return s.c
+}
+
+// Pipeline returns the pipeline of stream s
+func (s Stream[T]) Pipeline() *Pipeline {
+	return s.p
+}
+
+// Collect returns the values of stream s and the error of its pipeline,
+// which is stopped once s is closed.
+// On failure the values received before the pipeline was cancelled are returned.
+func (s Stream[T]) Collect() ([]T, error) {
+	a := ToSlice(s.c)
+	s.p.Stop()
+	return a, s.p.Err()
+}
+
+// Drain discards the values of stream s and returns the error of its pipeline,
+// which is stopped once s is closed.
+func (s Stream[T]) Drain() error {
+	Drain(s.c)
+	s.p.Stop()
+	return s.p.Err()
+}
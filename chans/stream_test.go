@@ -0,0 +1,112 @@
+package chans
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+	"github.com/redouan-rhazouani/goboost/concurrency"
+)
+
+func TestStream(t *testing.T) {
+	p, ctx := NewPipeline(context.Background())
+	square := func(ctx context.Context, v int) (int, error) { return v * v, nil }
+	s := TryMap(From(p, Range(ctx, 1, 5, 1)), square)
+	got, err := s.Collect()
+	if err != nil || !equal(got, []int{1, 4, 9, 16}) {
+		t.Errorf("Collect got=(%v, %v) want=([1 4 9 16], nil)", got, err)
+	}
+	if s.Pipeline() != p || p.Context() != ctx {
+		t.Errorf("stages must share the pipeline")
+	}
+	if ctx.Err() == nil {
+		t.Errorf("Collect must stop the pipeline")
+	}
+}
+
+func TestStreamStop(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	p, ctx := NewPipeline(context.Background())
+	s := From(p, RepeatForever(ctx, 1))
+	<-s.C()
+	p.Stop()
+	p.Stop()
+	for range s.C() { // closed once stopped
+	}
+	if err := p.Err(); err != nil || ctx.Err() == nil {
+		t.Errorf("got=(%v, %v) want=(nil, %v)", err, ctx.Err(), context.Canceled)
+	}
+	check()
+}
+
+func TestStreamFailure(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	errTooBig := errors.New("too big")
+	p, ctx := NewPipeline(context.Background())
+	s := From(p, RepeatForever(ctx, 1)) // infinite source stopped by the failure
+	s = Apply(s, func(ctx context.Context, in <-chan int) <-chan int {
+		return Scan(ctx, in, 0, func(acc, v int) int { return acc + v })
+	})
+	s = TryMap(s, func(ctx context.Context, v int) (int, error) {
+		if v > 3 {
+			return 0, errTooBig
+		}
+		return v, nil
+	})
+	got, err := s.Collect()
+	if !errors.Is(err, errTooBig) || !equal(got, []int{1, 2, 3}) {
+		t.Errorf("Collect got=(%v, %v) want=([1 2 3], %v)", got, err, errTooBig)
+	}
+	if ctx.Err() == nil {
+		t.Errorf("failure must cancel the pipeline")
+	}
+	p.Fail(errors.New("later")) // only the first error is recorded
+	if err := p.Err(); !errors.Is(err, errTooBig) {
+		t.Errorf("Err got=%v want=%v", err, errTooBig)
+	}
+	check()
+}
+
+func TestStreamInterop(t *testing.T) {
+	p, ctx := NewPipeline(context.Background())
+	a := Source(p, func(ctx context.Context, out chan<- int) error {
+		for _, v := range []int{1, 2} {
+			if !send(ctx, out, v) {
+				break
+			}
+		}
+		return nil
+	})
+	b := From(p, Repeat(10, 2))
+	merged := From(p, MergeContext(ctx, a.C(), b.C()))
+	sum := 0
+	for v := range merged.C() {
+		sum += v
+	}
+	if err := merged.Pipeline().Err(); sum != 23 || err != nil {
+		t.Errorf("sum got=(%d, %v) want=(23, nil)", sum, err)
+	}
+	// WhenAll results can feed a stream
+	errFoo := errors.New("foo")
+	p, ctx = NewPipeline(context.Background())
+	tasks := []concurrency.Task[int]{
+		func(context.Context) (int, error) { return 1, nil },
+		func(context.Context) (int, error) { return 0, errFoo },
+	}
+	if err := FromResults(p, concurrency.WhenAll(ctx, tasks...)).Drain(); !errors.Is(err, errFoo) {
+		t.Errorf("Drain got=%v want=%v", err, errFoo)
+	}
+}
+
+func TestStreamParentCancel(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	p, ctx := NewPipeline(parent)
+	s := From(p, RepeatForever(ctx, 1))
+	<-s.C()
+	time.AfterFunc(time.Millisecond, cancel)
+	if err := s.Drain(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Drain got=%v want=%v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,119 @@
+package chans
+
+import (
+	"context"
+	"sync"
+)
+
+// Tee splits in into n identical streams.
+// Every value is sent to each output before the next value is received,
+// so the slowest consumer sets the pace of all of them.
+// All outputs are closed when in is closed or ctx is done.
+func Tee[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	return Broadcast(ctx, in, n, 0, Block)
+}
+
+// Multicast shares a stream with any number of subscribers that may come and go,
+// replaying the last values to new subscribers.
+//
+// Every value is sent to each subscriber before the next value is received,
+// so the slowest subscriber sets the pace of all of them.
+// Subscribe and unsubscribe wait until the current value has been delivered.
+type Multicast[T any] struct {
+	mu     sync.Mutex // held while a value is delivered
+	size   int
+	replay ring[T]
+	subs   map[*multicastSub[T]]struct{}
+	closed bool
+}
+
+type multicastSub[T any] struct {
+	c    chan T
+	quit chan struct{} // closed on unsubscribe
+	once sync.Once
+}
+
+// NewMulticast starts multicasting the values of in, keeping the last replay values
+// for new subscribers. The channels of the subscribers are closed when in
+// is closed or ctx is done.
+func NewMulticast[T any](ctx context.Context, in <-chan T, replay int) *Multicast[T] {
+	if replay < 0 {
+		replay = 0
+	}
+	m := &Multicast[T]{size: replay, subs: make(map[*multicastSub[T]]struct{})}
+	go func() {
+		defer m.close()
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok || !m.deliver(ctx, v) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return m
+}
+
+// Subscribe returns a channel receiving the replayed values followed by
+// the values received from now on, and a function to unsubscribe.
+// Unsubscribing closes the channel. It is safe to call it more than once.
+func (m *Multicast[T]) Subscribe() (<-chan T, func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := &multicastSub[T]{c: make(chan T, m.replay.len()), quit: make(chan struct{})}
+	for i := 0; i < m.replay.len(); i++ {
+		s.c <- m.replay.at(i)
+	}
+	if m.closed {
+		close(s.c)
+		return s.c, func() {}
+	}
+	m.subs[s] = struct{}{}
+	return s.c, func() {
+		s.once.Do(func() {
+			close(s.quit) // release a delivery blocked on s
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if _, ok := m.subs[s]; ok {
+				delete(m.subs, s)
+				close(s.c)
+			}
+		})
+	}
+}
+
+// deliver records v for replay and sends it to every subscriber.
+// It reports false if ctx is done.
+func (m *Multicast[T]) deliver(ctx context.Context, v T) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.size > 0 {
+		if m.replay.len() == m.size {
+			m.replay.pop()
+		}
+		m.replay.push(v)
+	}
+	for s := range m.subs {
+		select {
+		case s.c <- v:
+		case <-s.quit:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// close closes the channels of all subscribers
+func (m *Multicast[T]) close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	for s := range m.subs {
+		close(s.c)
+	}
+	m.subs = nil
+}
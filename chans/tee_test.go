@@ -0,0 +1,91 @@
+package chans
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+)
+
+func TestTee(t *testing.T) {
+	ctx := context.Background()
+	xs := []int{1, 2, 3}
+	cs := Tee(ctx, FromSlice(xs), 3)
+	if len(cs) != 3 {
+		t.Fatalf("number of outputs got=%d want=3", len(cs))
+	}
+	for i, got := range collectAll(t, cs) {
+		if !equal(got, xs) {
+			t.Errorf("output %d got=%v want=%v", i, got, xs)
+		}
+	}
+}
+
+func TestMulticast(t *testing.T) {
+	in := make(chan int)
+	m := NewMulticast(context.Background(), in, 2)
+	s1, _ := m.Subscribe()
+	for i := 1; i <= 3; i++ {
+		in <- i
+		if v := <-s1; v != i {
+			t.Errorf("s1 got=%d want=%d", v, i)
+		}
+	}
+	// a late subscriber receives the last 2 values first
+	s2, _ := m.Subscribe()
+	got := make(chan []int)
+	go func() { got <- chanstest.Collect(t, s2, timeout) }()
+	in <- 4
+	if v := <-s1; v != 4 {
+		t.Errorf("s1 got=%d want=4", v)
+	}
+	close(in)
+	if rest := chanstest.Collect(t, s1, timeout); len(rest) != 0 {
+		t.Errorf("s1 got=%v want=[]", rest)
+	}
+	if v := <-got; !equal(v, []int{2, 3, 4}) {
+		t.Errorf("s2 got=%v want=[2 3 4]", v)
+	}
+	// subscribers after the end receive the replay only
+	s3, _ := m.Subscribe()
+	if v := chanstest.Collect(t, s3, timeout); !equal(v, []int{3, 4}) {
+		t.Errorf("s3 got=%v want=[3 4]", v)
+	}
+}
+
+func TestMulticastUnsubscribe(t *testing.T) {
+	in := make(chan int)
+	m := NewMulticast(context.Background(), in, 0)
+	s, unsubscribe := m.Subscribe()
+	in <- 1 // the delivery blocks on s
+	unsubscribe()
+	unsubscribe()
+	in <- 2 // 1 has been delivered
+	if v := chanstest.Collect(t, s, timeout); len(v) != 0 {
+		t.Errorf("got=%v want=[]", v)
+	}
+	close(in)
+}
+
+func TestMulticastNoReplay(t *testing.T) {
+	m := NewMulticast(context.Background(), FromSlice([]int{1, 2}), 0)
+	s, _ := m.Subscribe()
+	for range s { // may receive any suffix of the stream
+	}
+	s, _ = m.Subscribe()
+	if got := chanstest.Collect(t, s, timeout); len(got) != 0 {
+		t.Errorf("got=%v want=[]", got)
+	}
+}
+
+func TestMulticastCancel(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewMulticast(ctx, RepeatForever(ctx, 1), 1)
+	s, _ := m.Subscribe()
+	<-s
+	cancel()
+	check()
+	for range s {
+	}
+}
@@ -0,0 +1,118 @@
+package chans
+
+import (
+	"context"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/clock"
+	"github.com/redouan-rhazouani/goboost/concurrency"
+)
+
+// Throttle forwards the values of in at most rate values per second,
+// allowing bursts of up to burst values, see concurrency.Limiter.
+// The returned channel is closed when in is closed or ctx is done.
+// The time is read from the clock carried by ctx, see clock.From.
+func Throttle[T any](ctx context.Context, in <-chan T, rate float64, burst int) <-chan T {
+	return Limit(ctx, in, concurrency.NewLimiter(ctx, rate, burst))
+}
+
+// Limit forwards the values of in as limiter l allows them.
+// A limiter shared between several streams or tasks limits their combined rate.
+// The returned channel is closed when in is closed or ctx is done.
+func Limit[T any](ctx context.Context, in <-chan T, l *concurrency.Limiter) <-chan T {
+	return stage(ctx, in, 1, func(v T, emit func(T) bool) bool {
+		return l.Wait(ctx) == nil && emit(v)
+	})
+}
+
+// Debounce forwards a value of in only once d has passed without another value.
+// Values followed by another one within d are dropped.
+// The pending value is forwarded when in is closed.
+// The returned channel is closed when in is closed or ctx is done.
+// The time is read from the clock carried by ctx, see clock.From.
+func Debounce[T any](ctx context.Context, in <-chan T, d time.Duration) <-chan T {
+	r := make(chan T)
+	clk := clock.From(ctx)
+	go func() {
+		defer close(r)
+		var pending T
+		var timer clock.Timer
+		var expired <-chan time.Time
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if expired != nil {
+						send(ctx, r, pending)
+					}
+					return
+				}
+				pending = v
+				if timer == nil {
+					timer = clk.NewTimer(d)
+				} else {
+					if !timer.Stop() && expired != nil {
+						select { // drain the expiration not received yet
+						case <-expired:
+						default:
+						}
+					}
+					timer.Reset(d)
+				}
+				expired = timer.C()
+			case <-expired:
+				expired = nil
+				if !send(ctx, r, pending) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// Sample forwards every period d the latest value received from in during that period.
+// Nothing is forwarded for periods without values.
+// The returned channel is closed when in is closed or ctx is done.
+// The time is read from the clock carried by ctx, see clock.From.
+// It panics if d <= 0.
+func Sample[T any](ctx context.Context, in <-chan T, d time.Duration) <-chan T {
+	if d <= 0 {
+		panic("chans: sample period must be positive")
+	}
+	r := make(chan T)
+	ticker := clock.From(ctx).NewTicker(d)
+	go func() {
+		defer close(r)
+		defer ticker.Stop()
+		var latest T
+		fresh := false
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				latest, fresh = v, true
+			case <-ticker.C():
+				if !fresh {
+					continue
+				}
+				fresh = false
+				if !send(ctx, r, latest) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return r
+}
@@ -0,0 +1,105 @@
+package chans
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+	"github.com/redouan-rhazouani/goboost/clock"
+	"github.com/redouan-rhazouani/goboost/concurrency"
+)
+
+func TestThrottle(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	c := Throttle(ctx, emit(1, 2, 3, 4), 10, 2)
+	// the burst passes immediately
+	if a, b := <-c, <-c; a != 1 || b != 2 {
+		t.Errorf("burst got=[%d %d] want=[1 2]", a, b)
+	}
+	for _, want := range []int{3, 4} {
+		clk.BlockUntil(1)
+		chanstest.AssertNoValue(t, c)
+		clk.Advance(100 * time.Millisecond)
+		if v := <-c; v != want {
+			t.Errorf("got=%d want=%d", v, want)
+		}
+	}
+	chanstest.AssertClosed(t, c, timeout)
+}
+
+func TestLimit(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	l := concurrency.NewLimiter(ctx, 1, 1)
+	// both streams share the same budget
+	c1 := Limit(ctx, emit(1), l)
+	c2 := Limit(ctx, emit(2), l)
+	got := 0
+	select {
+	case v := <-c1:
+		got += v
+	case v := <-c2:
+		got += v
+	}
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	got += <-MergeN(c1, c2)
+	if got != 3 {
+		t.Errorf("sum got=%d want=3", got)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	in := make(chan int)
+	c := Debounce(ctx, in, time.Second)
+	in <- 1
+	in <- 2 // 1 is dropped
+	clk.BlockUntil(1)
+	clk.Advance(time.Second / 2)
+	in <- 3 // 2 is dropped
+	clk.Advance(time.Second / 2)
+	chanstest.AssertNoValue(t, c)
+	// the timer may be reset for 3 after time moved, move past both deadlines
+	clk.Advance(time.Second)
+	if v := <-c; v != 3 {
+		t.Errorf("got=%d want=3", v)
+	}
+	in <- 4
+	close(in) // the pending value is forwarded
+	if got := chanstest.Collect(t, c, timeout); !equal(got, []int{4}) {
+		t.Errorf("got=%v want=[4]", got)
+	}
+}
+
+func TestSample(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	in := make(chan int)
+	c := Sample(ctx, in, time.Second)
+	in <- 1
+	in <- 2
+	clk.Advance(time.Second)
+	if v := <-c; v != 2 {
+		t.Errorf("got=%d want=2", v)
+	}
+	clk.Advance(time.Second) // nothing received during this period
+	in <- 3
+	clk.Advance(time.Second)
+	if v := <-c; v != 3 {
+		t.Errorf("got=%d want=3", v)
+	}
+	close(in)
+	if got := chanstest.Collect(t, c, timeout); len(got) != 0 {
+		t.Errorf("got=%v want=[]", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Sample must panic for a zero period")
+		}
+	}()
+	Sample(ctx, in, 0)
+}
@@ -0,0 +1,216 @@
+package chans
+
+import (
+	"context"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+// Batch groups the values of in into slices of up to size values.
+//
+// A batch is emitted when it is full or maxDelay after its first value
+// was received, whichever comes first. A maxDelay <= 0 disables the time limit.
+// The last partial batch is emitted when in is closed.
+// The returned channel is closed when in is closed or ctx is done.
+// The time is read from the clock carried by ctx, see clock.From.
+func Batch[T any](ctx context.Context, in <-chan T, size int, maxDelay time.Duration) <-chan []T {
+	r := make(chan []T)
+	if size < 1 {
+		size = 1
+	}
+	clk := clock.From(ctx)
+	go func() {
+		defer close(r)
+		var batch []T
+		var timer clock.Timer
+		var expired <-chan time.Time
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, expired = nil, nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+			b := batch
+			batch = nil
+			return send(ctx, r, b)
+		}
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				if batch == nil {
+					batch = make([]T, 0, size)
+					if maxDelay > 0 {
+						timer = clk.NewTimer(maxDelay)
+						expired = timer.C()
+					}
+				}
+				batch = append(batch, v)
+				if len(batch) == size && !flush() {
+					return
+				}
+			case <-expired:
+				timer, expired = nil, nil
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// Window groups the values of in into consecutive, non-overlapping slices of size values.
+// The last partial window is emitted when in is closed.
+// The returned channel is closed when in is closed or ctx is done.
+func Window[T any](ctx context.Context, in <-chan T, size int) <-chan []T {
+	return Batch(ctx, in, size, 0)
+}
+
+// WindowTime groups the values of in received during consecutive periods of duration d.
+// Empty windows are not emitted. The last partial window is emitted when in is closed.
+// The returned channel is closed when in is closed or ctx is done.
+// The time is read from the clock carried by ctx, see clock.From.
+// It panics if d <= 0.
+func WindowTime[T any](ctx context.Context, in <-chan T, d time.Duration) <-chan []T {
+	if d <= 0 {
+		panic("chans: window duration must be positive")
+	}
+	r := make(chan []T)
+	ticker := clock.From(ctx).NewTicker(d)
+	go func() {
+		defer close(r)
+		defer ticker.Stop()
+		var window []T
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if len(window) > 0 {
+						send(ctx, r, window)
+					}
+					return
+				}
+				window = append(window, v)
+			case <-ticker.C():
+				if len(window) == 0 {
+					continue
+				}
+				if !send(ctx, r, window) {
+					return
+				}
+				window = nil
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// SlidingWindow emits the last size values of in every step values.
+// Windows overlap if step < size and leave values out if step > size.
+// Only full windows are emitted.
+// The returned channel is closed when in is closed or ctx is done.
+func SlidingWindow[T any](ctx context.Context, in <-chan T, size, step int) <-chan []T {
+	if size < 1 {
+		size = 1
+	}
+	if step < 1 {
+		step = 1
+	}
+	window := make([]T, 0, size)
+	skip := 0
+	return stage(ctx, in, 1, func(v T, emit func([]T) bool) bool {
+		if skip > 0 {
+			skip--
+			return true
+		}
+		window = append(window, v)
+		if len(window) < size {
+			return true
+		}
+		w := make([]T, size)
+		copy(w, window)
+		if step < size {
+			window = window[:copy(window, window[step:])]
+		} else {
+			window = window[:0]
+			skip = step - size
+		}
+		return emit(w)
+	})
+}
+
+// SlidingWindowTime emits every period the values of in received during the last d.
+// Windows overlap if period < d and leave values out if period > d.
+// Empty windows are not emitted. When in is closed, the values received during
+// the last d are emitted if any was received since the last window.
+// The returned channel is closed when in is closed or ctx is done.
+// The time is read from the clock carried by ctx, see clock.From.
+// It panics if period <= 0.
+func SlidingWindowTime[T any](ctx context.Context, in <-chan T, d, period time.Duration) <-chan []T {
+	if period <= 0 {
+		panic("chans: window period must be positive")
+	}
+	type entry struct {
+		t time.Time
+		v T
+	}
+	r := make(chan []T)
+	clk := clock.From(ctx)
+	ticker := clk.NewTicker(period)
+	go func() {
+		defer close(r)
+		defer ticker.Stop()
+		var entries []entry
+		fresh := false // a value was received since the last window
+		// window drops the values received before now-d and returns the others
+		window := func(now time.Time) []T {
+			start := now.Add(-d)
+			i := 0
+			for i < len(entries) && !entries[i].t.After(start) {
+				i++
+			}
+			entries = entries[:copy(entries, entries[i:])]
+			w := make([]T, len(entries))
+			for i, e := range entries {
+				w[i] = e.v
+			}
+			return w
+		}
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if w := window(clk.Now()); fresh && len(w) > 0 {
+						send(ctx, r, w)
+					}
+					return
+				}
+				entries = append(entries, entry{clk.Now(), v})
+				fresh = true
+			case now := <-ticker.C():
+				w := window(now)
+				if len(w) == 0 {
+					continue
+				}
+				fresh = false
+				if !send(ctx, r, w) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return r
+}
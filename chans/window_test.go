@@ -0,0 +1,172 @@
+package chans
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+func equalWindows[T comparable](x, y [][]T) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if !equal(x[i], y[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains[T comparable](xs []T, v T) bool {
+	for _, x := range xs {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBatchSize(t *testing.T) {
+	ctx := clock.With(context.Background(), chanstest.NewFakeClock())
+	got := chanstest.Collect(t, Batch(ctx, emit(1, 2, 3, 4, 5), 2, time.Second), timeout)
+	if want := [][]int{{1, 2}, {3, 4}, {5}}; !equalWindows(got, want) {
+		t.Errorf("Batch got=%v want=%v", got, want)
+	}
+	got = chanstest.Collect(t, Window(ctx, emit(1, 2, 3, 4, 5), 3), timeout)
+	if want := [][]int{{1, 2, 3}, {4, 5}}; !equalWindows(got, want) {
+		t.Errorf("Window got=%v want=%v", got, want)
+	}
+}
+
+func TestBatchDelay(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	in := make(chan int)
+	c := Batch(ctx, in, 3, time.Second)
+	in <- 1
+	in <- 2
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	if got := <-c; !equal(got, []int{1, 2}) {
+		t.Errorf("batch got=%v want=[1 2]", got)
+	}
+	// the delay starts with the first value of each batch
+	clk.Advance(time.Hour)
+	in <- 3
+	clk.BlockUntil(1)
+	clk.Advance(time.Second / 2)
+	in <- 4
+	clk.Advance(time.Second / 2)
+	if got := <-c; !equal(got, []int{3, 4}) {
+		t.Errorf("batch got=%v want=[3 4]", got)
+	}
+	// full batches stop their timer
+	in <- 5
+	in <- 6
+	in <- 7
+	if got := <-c; !equal(got, []int{5, 6, 7}) {
+		t.Errorf("batch got=%v want=[5 6 7]", got)
+	}
+	in <- 8
+	close(in)
+	if got := chanstest.Collect(t, c, timeout); !equalWindows(got, [][]int{{8}}) {
+		t.Errorf("batch got=%v want=[[8]]", got)
+	}
+}
+
+func TestWindowTime(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	in := make(chan int)
+	c := WindowTime(ctx, in, time.Second)
+	in <- 1
+	in <- 2
+	clk.Advance(time.Second)
+	if got := <-c; !equal(got, []int{1, 2}) {
+		t.Errorf("window got=%v want=[1 2]", got)
+	}
+	clk.Advance(time.Second) // empty window
+	in <- 3
+	close(in)
+	if got := chanstest.Collect(t, c, timeout); !equalWindows(got, [][]int{{3}}) {
+		t.Errorf("windows got=%v want=[[3]]", got)
+	}
+}
+
+func TestWindowTimePanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"WindowTime":        func() { WindowTime(context.Background(), emit(1), 0) },
+		"SlidingWindowTime": func() { SlidingWindowTime(context.Background(), emit(1), time.Second, 0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s must panic for a zero duration", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		size, step int
+		want       [][]int
+	}{
+		{3, 1, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}},
+		{2, 2, [][]int{{1, 2}, {3, 4}}},
+		{2, 3, [][]int{{1, 2}, {4, 5}}},
+		{6, 1, [][]int{}},
+	}
+	for _, tc := range tests {
+		got := chanstest.Collect(t, SlidingWindow(ctx, emit(1, 2, 3, 4, 5), tc.size, tc.step), timeout)
+		if !equalWindows(got, tc.want) {
+			t.Errorf("SlidingWindow(size=%d, step=%d) got=%v want=%v", tc.size, tc.step, got, tc.want)
+		}
+	}
+}
+
+func TestSlidingWindowTime(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	in := make(chan int)
+	c := SlidingWindowTime(ctx, in, 2*time.Second, time.Second)
+	// a value is timestamped once it is received, sending the next value
+	// guarantees that the previous one has been timestamped before time moves.
+	tests := []struct {
+		send    []int
+		in, out []int
+	}{
+		{[]int{1, 2}, []int{1}, nil},
+		{[]int{3, 4}, []int{3}, []int{1}},
+		{[]int{5, 6}, []int{5}, []int{1, 3}},
+	}
+	for i, tc := range tests {
+		for _, v := range tc.send {
+			in <- v
+		}
+		clk.Advance(time.Second)
+		got := <-c
+		for _, v := range tc.in {
+			if !contains(got, v) {
+				t.Errorf("window %d got=%v want %d in it", i, got, v)
+			}
+		}
+		for _, v := range tc.out {
+			if contains(got, v) {
+				t.Errorf("window %d got=%v want %d expired", i, got, v)
+			}
+		}
+	}
+	in <- 7 // the last window is emitted when in is closed between ticks
+	close(in)
+	if got := chanstest.Collect(t, c, timeout); !equalWindows(got, [][]int{{5, 6, 7}}) {
+		t.Errorf("last window got=%v want=[[5 6 7]]", got)
+	}
+}
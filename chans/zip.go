@@ -0,0 +1,172 @@
+package chans
+
+import (
+	"context"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/clock"
+	"github.com/redouan-rhazouani/goboost/tuple"
+)
+
+// Zip pairs the values of a and b in lockstep:
+// the i-th value of a is paired with the i-th value of b.
+// The returned channel is closed when either input is closed or ctx is done.
+func Zip[A, B any](ctx context.Context, a <-chan A, b <-chan B) <-chan tuple.Pair[A, B] {
+	r := make(chan tuple.Pair[A, B])
+	go func() {
+		defer close(r)
+		for {
+			var p tuple.Pair[A, B]
+			ca, cb := a, b
+			for ca != nil || cb != nil {
+				var ok bool
+				select {
+				case p.First, ok = <-ca:
+					ca = nil
+				case p.Second, ok = <-cb:
+					cb = nil
+				case <-ctx.Done():
+					return
+				}
+				if !ok {
+					return
+				}
+			}
+			if !send(ctx, r, p) {
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// CombineLatest emits the latest values of a and b each time either of them
+// receives a value, once both have received at least one.
+// The returned channel is closed when both inputs are closed or ctx is done,
+// and as soon as an input is closed before its first value, since no pair
+// can be emitted anymore.
+func CombineLatest[A, B any](ctx context.Context, a <-chan A, b <-chan B) <-chan tuple.Pair[A, B] {
+	r := make(chan tuple.Pair[A, B])
+	go func() {
+		defer close(r)
+		var p tuple.Pair[A, B]
+		var hasA, hasB bool
+		for a != nil || b != nil {
+			select {
+			case v, ok := <-a:
+				if !ok {
+					if a = nil; !hasA {
+						return // no pair can be emitted anymore
+					}
+					continue
+				}
+				p.First, hasA = v, true
+			case v, ok := <-b:
+				if !ok {
+					if b = nil; !hasB {
+						return
+					}
+					continue
+				}
+				p.Second, hasB = v, true
+			case <-ctx.Done():
+				return
+			}
+			if hasA && hasB && !send(ctx, r, p) {
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// Join pairs each value l of left with each value r of right having the same key,
+// lkey(l) == rkey(r), when they are received at most window apart.
+// Pairs are emitted as soon as the second value of a pair is received.
+// The returned channel is closed when both inputs are closed or ctx is done.
+// The time is read from the clock carried by ctx, see clock.From.
+func Join[L, R any, K comparable](ctx context.Context, left <-chan L, right <-chan R, lkey func(L) K, rkey func(R) K, window time.Duration) <-chan tuple.Pair[L, R] {
+	r := make(chan tuple.Pair[L, R])
+	clk := clock.From(ctx)
+	go func() {
+		defer close(r)
+		ls, rs := newJoinSide[L, K](), newJoinSide[R, K]()
+		for left != nil || right != nil {
+			select {
+			case v, ok := <-left:
+				if !ok {
+					left = nil
+					continue
+				}
+				now := clk.Now()
+				ls.evict(now, window)
+				rs.evict(now, window)
+				k := lkey(v)
+				for _, e := range rs.entries[k] {
+					if !send(ctx, r, tuple.MakePair(v, e.v)) {
+						return
+					}
+				}
+				ls.add(k, v, now)
+			case v, ok := <-right:
+				if !ok {
+					right = nil
+					continue
+				}
+				now := clk.Now()
+				ls.evict(now, window)
+				rs.evict(now, window)
+				k := rkey(v)
+				for _, e := range ls.entries[k] {
+					if !send(ctx, r, tuple.MakePair(e.v, v)) {
+						return
+					}
+				}
+				rs.add(k, v, now)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// joinEntry is a value received at time t
+type joinEntry[T any] struct {
+	t time.Time
+	v T
+}
+
+// joinSide holds the values of one side of a Join by key and in arrival order
+type joinSide[T any, K comparable] struct {
+	entries map[K][]joinEntry[T]
+	order   []K // keys in arrival order
+}
+
+func newJoinSide[T any, K comparable]() *joinSide[T, K] {
+	return &joinSide[T, K]{entries: make(map[K][]joinEntry[T])}
+}
+
+func (s *joinSide[T, K]) add(k K, v T, now time.Time) {
+	s.entries[k] = append(s.entries[k], joinEntry[T]{now, v})
+	s.order = append(s.order, k)
+}
+
+// evict removes the values received more than window before now
+func (s *joinSide[T, K]) evict(now time.Time, window time.Duration) {
+	start := now.Add(-window)
+	i := 0
+	for ; i < len(s.order); i++ {
+		k := s.order[i]
+		es := s.entries[k]
+		if !es[0].t.Before(start) {
+			break
+		}
+		if len(es) == 1 {
+			delete(s.entries, k)
+		} else {
+			s.entries[k] = es[1:]
+		}
+	}
+	s.order = s.order[:copy(s.order, s.order[i:])]
+}
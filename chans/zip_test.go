@@ -0,0 +1,107 @@
+package chans
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+	"github.com/redouan-rhazouani/goboost/clock"
+	"github.com/redouan-rhazouani/goboost/tuple"
+)
+
+func TestZip(t *testing.T) {
+	ctx := context.Background()
+	got := chanstest.Collect(t, Zip(ctx, emit(1, 2, 3), emit("a", "b")), timeout)
+	want := []tuple.Pair[int, string]{tuple.MakePair(1, "a"), tuple.MakePair(2, "b")}
+	if !equal(got, want) {
+		t.Errorf("Zip got=%v want=%v", got, want)
+	}
+	if got := chanstest.Collect(t, Zip(ctx, emit[int](), emit("a")), timeout); len(got) != 0 {
+		t.Errorf("Zip with empty input got=%v want=[]", got)
+	}
+}
+
+func TestCombineLatest(t *testing.T) {
+	ctx := context.Background()
+	a, b := make(chan int), make(chan string)
+	c := CombineLatest(ctx, a, b)
+	a <- 1
+	b <- "x"
+	if p := <-c; p != tuple.MakePair(1, "x") {
+		t.Errorf("got=%v want=(1, x)", p)
+	}
+	a <- 2
+	if p := <-c; p != tuple.MakePair(2, "x") {
+		t.Errorf("got=%v want=(2, x)", p)
+	}
+	close(a)
+	b <- "y"
+	if p := <-c; p != tuple.MakePair(2, "y") {
+		t.Errorf("got=%v want=(2, y)", p)
+	}
+	close(b)
+	chanstest.AssertClosed(t, c, timeout)
+	// an input closed before its first value closes the output
+	a, b = make(chan int), make(chan string)
+	c = CombineLatest(ctx, a, b)
+	close(b)
+	chanstest.AssertClosed(t, c, timeout)
+	close(a)
+}
+
+func TestJoin(t *testing.T) {
+	type order struct {
+		id   int
+		item string
+	}
+	type payment struct {
+		id     int
+		amount int
+	}
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	orders, payments := make(chan order), make(chan payment)
+	c := Join(ctx, orders, payments,
+		func(o order) int { return o.id },
+		func(p payment) int { return p.id },
+		time.Second)
+	check := func(want tuple.Pair[order, payment]) {
+		t.Helper()
+		if p := <-c; p != want {
+			t.Errorf("got=%v want=%v", p, want)
+		}
+	}
+	orders <- order{1, "a"}
+	payments <- payment{1, 10}
+	check(tuple.MakePair(order{1, "a"}, payment{1, 10}))
+	clk.Advance(2 * time.Second)
+	payments <- payment{1, 20} // order 1 is out of the window
+	orders <- order{2, "b"}
+	payments <- payment{2, 30}
+	check(tuple.MakePair(order{2, "b"}, payment{2, 30}))
+	orders <- order{1, "c"}
+	check(tuple.MakePair(order{1, "c"}, payment{1, 20}))
+	close(orders)
+	payments <- payment{2, 40} // the right side still joins after left is closed
+	check(tuple.MakePair(order{2, "b"}, payment{2, 40}))
+	close(payments)
+	chanstest.AssertClosed(t, c, timeout)
+}
+
+func TestZipCancel(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	a, b := RepeatContext(ctx, 1, 100), RepeatContext(ctx, "a", 100)
+	cs := []<-chan tuple.Pair[int, string]{
+		Zip(ctx, a, b),
+		CombineLatest(ctx, a, b),
+		Join(ctx, a, b, func(int) int { return 0 }, func(string) int { return 0 }, time.Second),
+	}
+	cancel()
+	check()
+	for _, c := range cs {
+		for range c {
+		}
+	}
+}
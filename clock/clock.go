@@ -0,0 +1,79 @@
+// Package clock abstracts the passage of time
+//
+// Time based algorithms read the current time and create timers through a Clock
+// so that tests can replace the real clock with a fake one and run deterministically.
+// Functions taking a context.Context find their clock with From;
+// a clock is attached to a context with With.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and creates timers and tickers
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// NewTimer creates a Timer that sends the current time on its channel after at least duration d
+	NewTimer(d time.Duration) Timer
+	// NewTicker creates a Ticker that sends the current time on its channel every period d.
+	// d must be greater than zero.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a single event, see time.Timer
+type Timer interface {
+	// C returns the channel on which the time is delivered
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing.
+	// It returns false if the timer has already expired or been stopped.
+	Stop() bool
+	// Reset changes the timer to expire after duration d.
+	// It returns true if the timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// Ticker delivers ticks at intervals, see time.Ticker
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered
+	C() <-chan time.Time
+	// Stop turns off the ticker
+	Stop()
+}
+
+// Real returns the clock backed by package time
+func Real() Clock {
+	return realClock{}
+}
+
+type contextKey struct{}
+
+// With returns a copy of ctx carrying clock c
+func With(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// From returns the clock carried by ctx or the real clock if there is none
+func From(ctx context.Context) Clock {
+	if c, ok := ctx.Value(contextKey{}).(Clock); ok && c != nil {
+		return c
+	}
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+func (realClock) NewTicker(d time.Duration) Ticker { return realTicker{time.NewTicker(d)} }
+
+type realTimer struct{ *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
+
+type realTicker struct{ *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
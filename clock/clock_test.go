@@ -0,0 +1,61 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type stoppedClock struct {
+	Clock
+	now time.Time
+}
+
+func (c stoppedClock) Now() time.Time { return c.now }
+
+func TestFrom(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := From(ctx).(realClock); !ok {
+		t.Errorf("From(context.Background()) got=%T want realClock", From(ctx))
+	}
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx = With(ctx, stoppedClock{Real(), now})
+	if got := From(ctx).Now(); !got.Equal(now) {
+		t.Errorf("From(ctx).Now() got=%v want=%v", got, now)
+	}
+	if _, ok := From(With(ctx, nil)).(realClock); !ok {
+		t.Errorf("From(With(ctx, nil)) got=%T want realClock", From(With(ctx, nil)))
+	}
+}
+
+func TestReal(t *testing.T) {
+	c := Real()
+	start := c.Now()
+	timer := c.NewTimer(time.Millisecond)
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+	if timer.Stop() {
+		t.Errorf("Stop() of an expired timer got=true want=false")
+	}
+	if timer.Reset(time.Hour) {
+		t.Errorf("Reset() of an expired timer got=true want=false")
+	}
+	if !timer.Stop() {
+		t.Errorf("Stop() of an active timer got=false want=true")
+	}
+	ticker := c.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ticker.C():
+		case <-time.After(time.Second):
+			t.Fatal("ticker did not tick")
+		}
+	}
+	if d := c.Now().Sub(start); d < time.Millisecond {
+		t.Errorf("elapsed time got=%v want>=%v", d, time.Millisecond)
+	}
+}
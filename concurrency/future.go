@@ -0,0 +1,286 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Future is a handle to the result of a task which may still be running
+type Future[T any] struct {
+	done      chan struct{}
+	mu        sync.Mutex
+	completed bool
+	callbacks []func() // called once the result is set
+	res       T
+	err       error
+	cancel    func()
+}
+
+// newFuture returns a pending future, cancel is called by Cancel
+func newFuture[T any](cancel func()) *Future[T] {
+	return &Future[T]{done: make(chan struct{}), cancel: cancel}
+}
+
+// failed returns a future completed with err
+func failed[T any](err error) *Future[T] {
+	f := newFuture[T](nil)
+	var zero T
+	f.complete(zero, err)
+	return f
+}
+
+// Async starts task in a new goroutine and returns its future.
+// The task's context is derived from ctx and is canceled by Cancel.
+// If ctx is already done, the task is not run.
+func Async[T any](ctx context.Context, task Task[T]) *Future[T] {
+	f, start := prepare(ctx, task)
+	if run := start(); run != nil {
+		go run()
+	}
+	return f
+}
+
+// prepare returns the future of task and start, which claims the task and
+// returns the function running it. If the future was canceled or ctx is done
+// before, start completes the future with the context's error and returns nil.
+func prepare[T any](ctx context.Context, task Task[T]) (*Future[T], func() func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	var started atomic.Bool
+	var zero T
+	f := newFuture[T](nil)
+	f.cancel = func() {
+		cancel()
+		if started.CompareAndSwap(false, true) {
+			f.complete(zero, context.Canceled)
+		}
+	}
+	start := func() func() {
+		if !started.CompareAndSwap(false, true) {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			cancel()
+			f.complete(zero, err)
+			return nil
+		}
+		return func() {
+			defer cancel()
+			res, err := task(ctx)
+			f.complete(res, err)
+		}
+	}
+	return f, start
+}
+
+// complete sets the result of f and reports whether f was still pending
+func (f *Future[T]) complete(res T, err error) bool {
+	f.mu.Lock()
+	if f.completed {
+		f.mu.Unlock()
+		return false
+	}
+	f.completed = true
+	f.res, f.err = res, err
+	callbacks := f.callbacks
+	f.callbacks = nil
+	close(f.done)
+	f.mu.Unlock()
+	for _, fn := range callbacks {
+		fn()
+	}
+	return true
+}
+
+// subscribe calls fn once f is completed, immediately if it already is
+func (f *Future[T]) subscribe(fn func()) {
+	f.mu.Lock()
+	if !f.completed {
+		f.callbacks = append(f.callbacks, fn)
+		f.mu.Unlock()
+		return
+	}
+	f.mu.Unlock()
+	fn()
+}
+
+// Done returns a channel which is closed when the result is available
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Get waits for the result of the task.
+// It returns ctx.Err() if ctx is done first, the task keeps running.
+func (f *Future[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.res, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Cancel cancels the context of the task.
+// It has no effect if the task already finished.
+func (f *Future[T]) Cancel() {
+	if f.cancel != nil {
+		f.cancel()
+	}
+}
+
+// Then returns the future of fn applied to the result of f once it succeeded.
+// fn runs in a new goroutine with a context derived from ctx.
+// If f fails, the returned future fails with the same error and fn is not called.
+// Canceling the returned future also cancels f.
+func Then[T, U any](ctx context.Context, f *Future[T], fn func(context.Context, T) (U, error)) *Future[U] {
+	r, start := prepare(ctx, func(ctx context.Context) (U, error) {
+		if f.err != nil {
+			var zero U
+			return zero, f.err
+		}
+		return fn(ctx, f.res)
+	})
+	cancel := r.cancel
+	r.cancel = func() {
+		f.Cancel()
+		cancel()
+	}
+	f.subscribe(func() {
+		if run := start(); run != nil {
+			go run()
+		}
+	})
+	return r
+}
+
+// Map returns the future of fn applied to the result of f once it succeeded.
+// fn runs in the goroutine completing f and must not block, use Then otherwise.
+// If f fails, the returned future fails with the same error and fn is not called.
+// Canceling the returned future cancels f.
+func Map[T, U any](f *Future[T], fn func(T) U) *Future[U] {
+	r := newFuture[U](f.Cancel)
+	f.subscribe(func() {
+		if f.err != nil {
+			var zero U
+			r.complete(zero, f.err)
+			return
+		}
+		r.complete(fn(f.res), nil)
+	})
+	return r
+}
+
+// AllOf returns a future of the results of fs in order, completed once all of them succeeded.
+// It fails with the error of the first future that fails and cancels the others.
+// Canceling the returned future cancels fs.
+func AllOf[T any](fs ...*Future[T]) *Future[[]T] {
+	r := newFuture[[]T](func() { cancelAll(fs) })
+	results := make([]T, len(fs))
+	if len(fs) == 0 {
+		r.complete(results, nil)
+		return r
+	}
+	var mu sync.Mutex
+	remaining := len(fs)
+	for i, f := range fs {
+		f.subscribe(func() {
+			if f.err != nil {
+				if r.complete(nil, f.err) {
+					cancelAll(fs)
+				}
+				return
+			}
+			mu.Lock()
+			results[i] = f.res
+			remaining--
+			last := remaining == 0
+			mu.Unlock()
+			if last {
+				r.complete(results, nil)
+			}
+		})
+	}
+	return r
+}
+
+// AnyOf returns a future of the result of the first of fs that succeeds and cancels the others.
+// If all of them fail, it fails with an error joining their errors in order.
+// If fs is empty, the returned future is completed with the zero value and no error.
+// Canceling the returned future cancels fs.
+func AnyOf[T any](fs ...*Future[T]) *Future[T] {
+	first := anyOf(fs)
+	return Map(first, func(r TaskResult[T]) T { return r.Result })
+}
+
+// anyOf is like AnyOf but the result holds the index of the successful future,
+// -1 if there is none
+func anyOf[T any](fs []*Future[T]) *Future[TaskResult[T]] {
+	if len(fs) == 0 {
+		r := newFuture[TaskResult[T]](nil)
+		r.complete(TaskResult[T]{Index: -1}, nil)
+		return r
+	}
+	q := quorumOf(fs, 1)
+	r := newFuture[TaskResult[T]](q.Cancel)
+	q.subscribe(func() {
+		if q.err != nil {
+			r.complete(TaskResult[T]{Err: q.err, Index: -1}, q.err)
+			return
+		}
+		r.complete(q.res[0], nil)
+	})
+	return r
+}
+
+// quorumOf returns a future of the results of the first n of fs that succeed, in completion order.
+// It fails as soon as fewer than n of them can succeed, with an error joining
+// the errors of the failed futures in order. Once completed, it cancels the others.
+// n must be in [1, len(fs)].
+func quorumOf[T any](fs []*Future[T], n int) *Future[[]TaskResult[T]] {
+	r := newFuture[[]TaskResult[T]](func() { cancelAll(fs) })
+	var mu sync.Mutex
+	finished := false
+	results := make([]TaskResult[T], 0, n)
+	errs := make([]error, len(fs))
+	failures := 0
+	for i, f := range fs {
+		f.subscribe(func() {
+			mu.Lock()
+			if finished {
+				mu.Unlock()
+				return
+			}
+			if f.err == nil {
+				results = append(results, TaskResult[T]{f.res, nil, i})
+			} else {
+				errs[i] = f.err
+				failures++
+			}
+			var err error
+			if failures > len(fs)-n {
+				err = errors.Join(errs...)
+			}
+			done := len(results) == n || err != nil
+			finished = done
+			mu.Unlock()
+			if done {
+				if err != nil {
+					r.complete(nil, err)
+				} else {
+					r.complete(results, nil)
+				}
+				cancelAll(fs)
+			}
+		})
+	}
+	return r
+}
+
+// cancelAll cancels the futures fs
+func cancelAll[T any](fs []*Future[T]) {
+	for _, f := range fs {
+		f.Cancel()
+	}
+}
@@ -0,0 +1,137 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+)
+
+func TestFuture(t *testing.T) {
+	ctx := context.Background()
+	canceled := 0
+	f := newFuture[int](func() { canceled++ })
+	chanstest.AssertNoValue(t, f.Done())
+	short, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	if _, err := f.Get(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get got=%v want=%v", err, context.DeadlineExceeded)
+	}
+	if !f.complete(1, nil) || f.complete(2, nil) {
+		t.Errorf("only the first complete must set the result")
+	}
+	chanstest.AssertClosed(t, f.Done(), time.Second)
+	if v, err := f.Get(ctx); v != 1 || err != nil {
+		t.Errorf("Get got=(%d, %v) want=(1, nil)", v, err)
+	}
+	f.Cancel()
+	if canceled != 1 {
+		t.Errorf("Cancel calls got=%d want=1", canceled)
+	}
+	errFoo := errors.New("foo")
+	if _, err := failed[int](errFoo).Get(ctx); err != errFoo {
+		t.Errorf("Get got=%v want=%v", err, errFoo)
+	}
+	failed[int](errFoo).Cancel() // no cancel func
+}
+
+func TestAsync(t *testing.T) {
+	ctx := context.Background()
+	f := Async(ctx, newTask(ctx, "Task-1", 1, time.Millisecond, nil))
+	if v, err := f.Get(ctx); v != 1 || err != nil {
+		t.Errorf("got=(%d, %v) want=(1, nil)", v, err)
+	}
+	f = Async(ctx, newTask(ctx, "Task-2", 2, time.Second, nil))
+	f.Cancel()
+	if _, err := f.Get(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled got=%v want=%v", err, context.Canceled)
+	}
+	done, cancel := context.WithCancel(ctx)
+	cancel()
+	run := false
+	f = Async(done, func(context.Context) (int, error) { run = true; return 1, nil })
+	if _, err := f.Get(ctx); !errors.Is(err, context.Canceled) || run {
+		t.Errorf("got=(%v, run=%v) want=(%v, run=false)", err, run, context.Canceled)
+	}
+}
+
+func TestThenMap(t *testing.T) {
+	ctx := context.Background()
+	errFoo := errors.New("foo")
+	double := func(ctx context.Context, v int) (int, error) { return 2 * v, nil }
+	f := Then(ctx, Async(ctx, newTask(ctx, "Task-1", 1, time.Millisecond, nil)), double)
+	if v, err := f.Get(ctx); v != 2 || err != nil {
+		t.Errorf("Then got=(%d, %v) want=(2, nil)", v, err)
+	}
+	called := false
+	f = Then(ctx, failed[int](errFoo), func(ctx context.Context, v int) (int, error) {
+		called = true
+		return v, nil
+	})
+	if _, err := f.Get(ctx); err != errFoo || called {
+		t.Errorf("Then of a failed future got=(%v, called=%v) want=(%v, called=false)", err, called, errFoo)
+	}
+	s := Map(Async(ctx, newTask(ctx, "Task-1", 3, 0, nil)), func(v int) string { return fmt.Sprint(v) })
+	if v, err := s.Get(ctx); v != "3" || err != nil {
+		t.Errorf("Map got=(%q, %v) want=(3, nil)", v, err)
+	}
+	if _, err := Map(failed[int](errFoo), func(v int) int { return v }).Get(ctx); err != errFoo {
+		t.Errorf("Map of a failed future got=%v want=%v", err, errFoo)
+	}
+	// canceling the chain cancels the running source
+	src := Async(ctx, newTask(ctx, "Task-1", 1, time.Second, nil))
+	Map(Then(ctx, src, double), func(v int) int { return v }).Cancel()
+	if _, err := src.Get(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("source got=%v want=%v", err, context.Canceled)
+	}
+}
+
+func TestAllOf(t *testing.T) {
+	ctx := context.Background()
+	errFoo := errors.New("foo")
+	r := AllOf(
+		Async(ctx, newTask(ctx, "Task-1", 1, 3*time.Millisecond, nil)),
+		Async(ctx, newTask(ctx, "Task-2", 2, time.Millisecond, nil)),
+	)
+	if v, err := r.Get(ctx); err != nil || len(v) != 2 || v[0] != 1 || v[1] != 2 {
+		t.Errorf("got=(%v, %v) want=([1 2], nil)", v, err)
+	}
+	if v, err := AllOf[int]().Get(ctx); len(v) != 0 || err != nil {
+		t.Errorf("empty got=(%v, %v) want=([], nil)", v, err)
+	}
+	slow := Async(ctx, newTask(ctx, "Task-1", 1, time.Second, nil))
+	r = AllOf(slow, Async(ctx, newTask(ctx, "Task-2", 2, 0, errFoo)))
+	if _, err := r.Get(ctx); !errors.Is(err, errFoo) {
+		t.Errorf("got=%v want=%v", err, errFoo)
+	}
+	if _, err := slow.Get(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("the other futures must be canceled, got=%v", err)
+	}
+}
+
+func TestAnyOf(t *testing.T) {
+	ctx := context.Background()
+	errFoo := errors.New("foo")
+	slow := Async(ctx, newTask(ctx, "Task-1", 1, time.Second, nil))
+	r := AnyOf(
+		slow,
+		Async(ctx, newTask(ctx, "Task-2", 2, time.Millisecond, nil)),
+		Async(ctx, newTask(ctx, "Task-3", 3, 0, errFoo)),
+	)
+	if v, err := r.Get(ctx); v != 2 || err != nil {
+		t.Errorf("got=(%d, %v) want=(2, nil)", v, err)
+	}
+	if _, err := slow.Get(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("the other futures must be canceled, got=%v", err)
+	}
+	errBar := errors.New("bar")
+	if _, err := AnyOf(failed[int](errFoo), failed[int](errBar)).Get(ctx); !errors.Is(err, errFoo) || !errors.Is(err, errBar) {
+		t.Errorf("all failed got=%v want both %v and %v", err, errFoo, errBar)
+	}
+	if v, err := AnyOf[int]().Get(ctx); v != 0 || err != nil {
+		t.Errorf("empty got=(%d, %v) want=(0, nil)", v, err)
+	}
+}
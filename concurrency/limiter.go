@@ -0,0 +1,100 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+// Limiter is a token bucket rate limiter.
+//
+// The bucket holds up to burst tokens and is refilled with rate tokens per second.
+// Each event consumes one token. A rate <= 0 allows burst events in total.
+// A Limiter is safe for concurrent use.
+type Limiter struct {
+	mu     sync.Mutex
+	clock  clock.Clock
+	rate   float64
+	burst  float64
+	tokens float64   // may be negative when waiters reserved future tokens
+	last   time.Time // last time tokens was updated
+}
+
+// NewLimiter returns a full Limiter allowing rate events per second with bursts of up to burst events.
+// The time is read from the clock carried by ctx, see clock.From.
+func NewLimiter(ctx context.Context, rate float64, burst int) *Limiter {
+	c := clock.From(ctx)
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{clock: c, rate: rate, burst: float64(burst), tokens: float64(burst), last: c.Now()}
+}
+
+// Allow reports whether an event may happen now and consumes a token if so
+func (l *Limiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Wait blocks until an event may happen and consumes a token.
+// It returns ctx.Err() if ctx is done first, in which case no token is consumed.
+// Waiters are served in the order they called Wait.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.refill()
+	l.tokens--
+	deficit := -l.tokens
+	l.mu.Unlock()
+	if deficit <= 0 {
+		return nil
+	}
+	var expired <-chan time.Time
+	if l.rate > 0 {
+		timer := l.clock.NewTimer(time.Duration(deficit / l.rate * float64(time.Second)))
+		defer timer.Stop()
+		expired = timer.C()
+	}
+	select {
+	case <-expired:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++ // give back the reserved token
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// refill adds the tokens accumulated since the last update
+func (l *Limiter) refill() {
+	now := l.clock.Now()
+	if l.rate > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
+
+// RateLimit returns a task waiting on limiter l before running task
+func RateLimit[T any](task Task[T], l *Limiter) Task[T] {
+	return func(ctx context.Context) (T, error) {
+		if err := l.Wait(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+		return task(ctx)
+	}
+}
@@ -0,0 +1,95 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	l := NewLimiter(ctx, 1, 2)
+	for i, want := range []bool{true, true, false} {
+		if got := l.Allow(); got != want {
+			t.Errorf("Allow() #%d got=%v want=%v", i, got, want)
+		}
+	}
+	clk.Advance(time.Second)
+	if !l.Allow() || l.Allow() {
+		t.Errorf("one token must be available after one second")
+	}
+	clk.Advance(time.Hour) // the bucket holds at most burst tokens
+	for i, want := range []bool{true, true, false} {
+		if got := l.Allow(); got != want {
+			t.Errorf("Allow() #%d got=%v want=%v", i, got, want)
+		}
+	}
+	l = NewLimiter(ctx, 0, 0)
+	clk.Advance(time.Hour)
+	if !l.Allow() || l.Allow() {
+		t.Errorf("rate=0 must allow burst events in total")
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	l := NewLimiter(ctx, 10, 1)
+	if err := l.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() { done <- l.Wait(ctx) }()
+	clk.BlockUntil(1)
+	clk.Advance(50 * time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("Wait returned early err=%v", err)
+	default:
+	}
+	clk.Advance(50 * time.Millisecond)
+	if err := <-done; err != nil {
+		t.Errorf("Wait got=%v want nil", err)
+	}
+	// cancellation gives the reserved token back
+	ctx, cancel := context.WithCancel(ctx)
+	go func() { done <- l.Wait(ctx) }()
+	clk.BlockUntil(1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait got=%v want %v", err, context.Canceled)
+	}
+	clk.Advance(100 * time.Millisecond)
+	if !l.Allow() {
+		t.Errorf("Allow() after cancelled Wait got=false want=true")
+	}
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait with cancelled ctx got=%v want %v", err, context.Canceled)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	l := NewLimiter(ctx, 1, 1)
+	task := RateLimit(newTask(ctx, "Task-1", 1, 0, nil), l)
+	c := WhenAll(ctx, task, task)
+	if r := <-c; r.Result != 1 || r.Err != nil {
+		t.Errorf("Result got=%v want={1, nil}", r)
+	}
+	clk.BlockUntil(1)
+	select {
+	case r := <-c:
+		t.Fatalf("second task ran before the limiter allowed it: %v", r)
+	default:
+	}
+	clk.Advance(time.Second)
+	if r := <-c; r.Result != 1 || r.Err != nil {
+		t.Errorf("Result got=%v want={1, nil}", r)
+	}
+}
@@ -0,0 +1,11 @@
+package concurrency
+
+import (
+	"testing"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+)
+
+func TestMain(m *testing.M) {
+	chanstest.VerifyTestMain(m)
+}
@@ -0,0 +1,324 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+// ErrPoolClosed is the error of tasks submitted to a pool being shut down or dropped by Stop
+var ErrPoolClosed = errors.New("concurrency: pool closed")
+
+// ErrPoolFull is the error of tasks rejected because the queue of a pool is full
+var ErrPoolFull = errors.New("concurrency: pool queue full")
+
+// QueuePolicy tells Submit what to do when the queue of a pool is full
+type QueuePolicy int
+
+const (
+	// Backpressure blocks Submit until the queue has room
+	Backpressure QueuePolicy = iota
+	// Reject fails the task with ErrPoolFull
+	Reject
+)
+
+// PoolConfig configures a Pool
+type PoolConfig struct {
+	// Workers is the number of workers always running, at least 1
+	Workers int
+	// MaxWorkers makes the pool elastic if greater than Workers:
+	// extra workers are started while all workers are busy.
+	MaxWorkers int
+	// IdleTimeout is how long an extra worker waits for a task before it exits, one minute if <= 0
+	IdleTimeout time.Duration
+	// QueueSize is the number of tasks which can wait for a worker
+	QueueSize int
+	// Policy is applied when the queue is full
+	Policy QueuePolicy
+	// Hooks report metrics
+	Hooks PoolHooks
+}
+
+// PoolHooks are called to report the metrics of a Pool, nil hooks are skipped.
+// They are called from the goroutines of the pool and must not block.
+type PoolHooks struct {
+	// QueueDepth reports the number of tasks waiting for a worker when it changes
+	QueueDepth func(n int)
+	// ActiveWorkers reports the number of workers running a task when it changes
+	ActiveWorkers func(n int)
+	// TaskLatency reports that a task waited for wait in the queue and then ran for run
+	TaskLatency func(wait, run time.Duration)
+}
+
+// Pool runs tasks on a set of long-lived workers.
+// A Pool is safe for concurrent use.
+type Pool[T any] struct {
+	cfg   PoolConfig
+	clock clock.Clock // measures latencies and idle timeouts
+	queue chan *job[T]
+	ctx   context.Context // parent of the tasks' contexts, canceled by Stop
+	stop  context.CancelFunc
+	quit  chan struct{} // closed when the pool stops accepting tasks
+	once  sync.Once
+
+	mu     sync.RWMutex // held for reading while sending to queue
+	closed bool
+
+	wg      sync.WaitGroup // running workers
+	workers atomic.Int32
+	idle    atomic.Int32
+	active  atomic.Int32
+}
+
+// job states
+const (
+	pending int32 = iota
+	running
+	finished
+)
+
+// job is a submitted task
+type job[T any] struct {
+	f       *Future[T]
+	ctx     context.Context
+	task    Task[T]
+	queued  time.Time
+	state   atomic.Int32
+	release func()
+}
+
+// NewPool returns a pool configured with cfg and starts its workers.
+// The contexts of the tasks carry the values of ctx, but canceling ctx
+// does not stop the pool, see Stop and Shutdown.
+// The time is read from the clock carried by ctx, see clock.From.
+func NewPool[T any](ctx context.Context, cfg PoolConfig) *Pool[T] {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.MaxWorkers < cfg.Workers {
+		cfg.MaxWorkers = cfg.Workers
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = time.Minute
+	}
+	if cfg.QueueSize < 0 {
+		cfg.QueueSize = 0
+	}
+	clk := clock.From(ctx)
+	ctx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	p := &Pool[T]{
+		cfg:   cfg,
+		clock: clk,
+		queue: make(chan *job[T], cfg.QueueSize),
+		ctx:   ctx,
+		stop:  stop,
+		quit:  make(chan struct{}),
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		p.workers.Add(1)
+		p.wg.Add(1)
+		go p.worker(false)
+	}
+	return p
+}
+
+// Submit queues task to be run by a worker and returns its future.
+// The task's context is derived from ctx and is also canceled by Stop.
+// If the queue is full, Submit blocks until it has room or fails the task
+// with ErrPoolFull, depending on the pool's policy. While blocked, Submit
+// fails the task with ctx.Err() if ctx is done and with ErrPoolClosed
+// if the pool is shut down.
+func (p *Pool[T]) Submit(ctx context.Context, task Task[T]) *Future[T] {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return failed[T](ErrPoolClosed)
+	}
+	j := p.newJob(ctx, task)
+	p.grow()
+	if p.cfg.Policy == Reject {
+		select {
+		case p.queue <- j:
+		default:
+			j.fail(ErrPoolFull)
+			return j.f
+		}
+	} else {
+		select {
+		case p.queue <- j:
+		case <-ctx.Done():
+			j.fail(ctx.Err())
+			return j.f
+		case <-p.quit:
+			j.fail(ErrPoolClosed)
+			return j.f
+		}
+	}
+	p.queueDepth()
+	return j.f
+}
+
+// Shutdown stops accepting tasks and waits until the queued and running tasks finished.
+// It returns ctx.Err() if ctx is done first, the tasks keep running then.
+// It's ok to call Shutdown after Stop to wait for the canceled tasks to return.
+func (p *Pool[T]) Shutdown(ctx context.Context) error {
+	p.close()
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		p.stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop stops accepting tasks, cancels the context of the running tasks
+// and fails the queued ones with ErrPoolClosed.
+// It does not wait for the running tasks to return.
+func (p *Pool[T]) Stop() {
+	p.close()
+	p.stop()
+}
+
+// close stops accepting tasks and lets the workers exit once the queue is empty
+func (p *Pool[T]) close() {
+	p.once.Do(func() { close(p.quit) }) // wake up blocked submitters
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+}
+
+// newJob returns a pending job running task with a context derived from ctx and p.ctx
+func (p *Pool[T]) newJob(ctx context.Context, task Task[T]) *job[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(p.ctx, cancel)
+	j := &job[T]{ctx: ctx, task: task, queued: p.clock.Now()}
+	j.release = func() {
+		stop()
+		cancel()
+	}
+	j.f = newFuture[T](func() {
+		cancel()
+		j.fail(context.Canceled) // if still queued
+	})
+	return j
+}
+
+// fail finishes j with err if it has not started
+func (j *job[T]) fail(err error) {
+	if j.state.CompareAndSwap(pending, finished) {
+		var zero T
+		j.finish(zero, err)
+	}
+}
+
+// finish releases the resources of j and completes its future
+func (j *job[T]) finish(res T, err error) {
+	j.release()
+	j.f.complete(res, err)
+}
+
+// grow starts an extra worker if no worker is available for the next task
+func (p *Pool[T]) grow() {
+	if int(p.idle.Load()) > len(p.queue) {
+		return
+	}
+	for {
+		n := p.workers.Load()
+		if n >= int32(p.cfg.MaxWorkers) {
+			return
+		}
+		if p.workers.CompareAndSwap(n, n+1) {
+			p.wg.Add(1)
+			go p.worker(true)
+			return
+		}
+	}
+}
+
+// worker runs queued tasks until the queue is closed.
+// An extra worker also exits after being idle for the idle timeout.
+func (p *Pool[T]) worker(extra bool) {
+	defer p.wg.Done()
+	defer p.workers.Add(-1)
+	var timeout <-chan time.Time
+	var timer clock.Timer
+	if extra {
+		timer = p.clock.NewTimer(p.cfg.IdleTimeout)
+		defer timer.Stop()
+		timeout = timer.C()
+	}
+	for {
+		p.idle.Add(1)
+		var j *job[T]
+		ok := false
+		select {
+		case j, ok = <-p.queue:
+		case <-timeout:
+		}
+		p.idle.Add(-1)
+		if !ok {
+			return
+		}
+		p.queueDepth()
+		p.run(j)
+		if extra {
+			if !timer.Stop() {
+				select {
+				case <-timeout:
+				default:
+				}
+			}
+			timer.Reset(p.cfg.IdleTimeout)
+		}
+	}
+}
+
+// run runs the task of j unless it was canceled while queued or the pool is stopped
+func (p *Pool[T]) run(j *job[T]) {
+	if !j.state.CompareAndSwap(pending, running) {
+		return
+	}
+	var zero T
+	if p.ctx.Err() != nil {
+		j.finish(zero, ErrPoolClosed)
+		return
+	}
+	if err := j.ctx.Err(); err != nil {
+		j.finish(zero, err)
+		return
+	}
+	hooks := p.cfg.Hooks
+	if n := p.active.Add(1); hooks.ActiveWorkers != nil {
+		hooks.ActiveWorkers(int(n))
+	}
+	start := p.clock.Now()
+	res, err := j.task(j.ctx)
+	end := p.clock.Now()
+	if n := p.active.Add(-1); hooks.ActiveWorkers != nil {
+		hooks.ActiveWorkers(int(n))
+	}
+	if hooks.TaskLatency != nil {
+		hooks.TaskLatency(start.Sub(j.queued), end.Sub(start))
+	}
+	j.finish(res, err)
+}
+
+// queueDepth reports the number of queued tasks
+func (p *Pool[T]) queueDepth() {
+	if f := p.cfg.Hooks.QueueDepth; f != nil {
+		f(len(p.queue))
+	}
+}
@@ -0,0 +1,282 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+// gate returns a task which blocks until release is closed or its context is done,
+// started receives a value when the task starts.
+func gate(v int, started chan<- int, release <-chan struct{}) Task[int] {
+	return func(ctx context.Context) (int, error) {
+		started <- v
+		select {
+		case <-release:
+			return v, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+func TestPoolSubmit(t *testing.T) {
+	ctx := context.Background()
+	var peak, running int32
+	p := NewPool[int](ctx, PoolConfig{Workers: 3, QueueSize: 10})
+	fs := make([]*Future[int], 20)
+	for i := range fs {
+		fs[i] = p.Submit(ctx, func(ctx context.Context) (int, error) {
+			r := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for p := atomic.LoadInt32(&peak); r > p && !atomic.CompareAndSwapInt32(&peak, p, r); p = atomic.LoadInt32(&peak) {
+			}
+			time.Sleep(100 * time.Microsecond)
+			return i * i, nil
+		})
+	}
+	for i, f := range fs {
+		if v, err := f.Get(ctx); v != i*i || err != nil {
+			t.Errorf("task %d got=(%d, %v) want=(%d, nil)", i, v, err, i*i)
+		}
+	}
+	if p := atomic.LoadInt32(&peak); p > 3 {
+		t.Errorf("%d tasks were running at once, want at most 3", p)
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown got=%v want=nil", err)
+	}
+}
+
+func TestPoolReject(t *testing.T) {
+	ctx := context.Background()
+	started, release := make(chan int, 1), make(chan struct{})
+	p := NewPool[int](ctx, PoolConfig{Workers: 1, QueueSize: 1, Policy: Reject})
+	f1 := p.Submit(ctx, gate(1, started, release))
+	<-started
+	f2 := p.Submit(ctx, gate(2, started, release)) // queued
+	f3 := p.Submit(ctx, gate(3, started, release))
+	if _, err := f3.Get(ctx); !errors.Is(err, ErrPoolFull) {
+		t.Errorf("got=%v want=%v", err, ErrPoolFull)
+	}
+	close(release)
+	for _, f := range []*Future[int]{f1, f2} {
+		if _, err := f.Get(ctx); err != nil {
+			t.Error(err)
+		}
+	}
+	p.Shutdown(ctx)
+}
+
+func TestPoolBackpressure(t *testing.T) {
+	ctx := context.Background()
+	started, release := make(chan int, 2), make(chan struct{})
+	p := NewPool[int](ctx, PoolConfig{Workers: 1})
+	p.Submit(ctx, gate(1, started, release))
+	<-started
+	submitted := make(chan *Future[int])
+	go func() { submitted <- p.Submit(ctx, gate(2, started, release)) }()
+	chanstest.AssertNoValue(t, submitted)
+	close(release)
+	f, _ := chanstest.Receive(t, submitted, time.Second)
+	if v, err := f.Get(ctx); v != 2 || err != nil {
+		t.Errorf("got=(%d, %v) want=(2, nil)", v, err)
+	}
+
+	release = make(chan struct{})
+	p.Submit(ctx, gate(3, started, release))
+	<-started
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	if _, err := p.Submit(ctx, gate(4, started, release)).Get(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("blocked Submit got=%v want=%v", err, context.DeadlineExceeded)
+	}
+	close(release)
+	p.Shutdown(context.Background())
+}
+
+func TestPoolShutdown(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx := context.Background()
+	started, release := make(chan int, 3), make(chan struct{})
+	p := NewPool[int](ctx, PoolConfig{Workers: 1, QueueSize: 2})
+	fs := []*Future[int]{
+		p.Submit(ctx, gate(1, started, release)),
+		p.Submit(ctx, gate(2, started, release)),
+		p.Submit(ctx, gate(3, started, release)),
+	}
+	<-started
+	expired, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := p.Shutdown(expired); !errors.Is(err, context.Canceled) {
+		t.Errorf("Shutdown got=%v want=%v", err, context.Canceled)
+	}
+	if _, err := p.Submit(ctx, gate(4, started, release)).Get(ctx); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit after Shutdown got=%v want=%v", err, ErrPoolClosed)
+	}
+	close(release)
+	if err := p.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown got=%v want=nil", err)
+	}
+	for i, f := range fs {
+		if v, err := f.Get(ctx); v != i+1 || err != nil {
+			t.Errorf("queued task %d got=(%d, %v) want=(%d, nil)", i, v, err, i+1)
+		}
+	}
+	check()
+}
+
+func TestPoolStop(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx := context.Background()
+	started, release := make(chan int, 2), make(chan struct{})
+	p := NewPool[int](ctx, PoolConfig{Workers: 1, QueueSize: 1})
+	running := p.Submit(ctx, gate(1, started, release))
+	queued := p.Submit(ctx, gate(2, started, release))
+	<-started
+	p.Stop()
+	if _, err := running.Get(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("running task got=%v want=%v", err, context.Canceled)
+	}
+	if _, err := queued.Get(ctx); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("queued task got=%v want=%v", err, ErrPoolClosed)
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown after Stop got=%v want=nil", err)
+	}
+	check()
+}
+
+func TestPoolCancel(t *testing.T) {
+	ctx := context.Background()
+	started, release := make(chan int, 2), make(chan struct{})
+	p := NewPool[int](ctx, PoolConfig{Workers: 1, QueueSize: 1})
+	running := p.Submit(ctx, gate(1, started, release))
+	queued := p.Submit(ctx, gate(2, started, release))
+	<-started
+	queued.Cancel()
+	if _, err := queued.Get(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled queued task got=%v want=%v", err, context.Canceled)
+	}
+	running.Cancel()
+	if _, err := running.Get(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled running task got=%v want=%v", err, context.Canceled)
+	}
+	p.Shutdown(ctx)
+	chanstest.AssertNoValue(t, started) // the canceled queued task never started
+}
+
+func TestPoolElastic(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	var mu sync.Mutex
+	var active []int
+	p := NewPool[int](ctx, PoolConfig{
+		Workers:     1,
+		MaxWorkers:  3,
+		IdleTimeout: time.Second,
+		QueueSize:   1,
+		Hooks: PoolHooks{ActiveWorkers: func(n int) {
+			mu.Lock()
+			active = append(active, n)
+			mu.Unlock()
+		}},
+	})
+	started, release := make(chan int, 4), make(chan struct{})
+	fs := make([]*Future[int], 4)
+	for i := range fs {
+		fs[i] = p.Submit(ctx, gate(i, started, release))
+		if i < 3 {
+			<-started // a new worker is started while the others are busy
+		}
+	}
+	chanstest.AssertNoValue(t, started) // at most MaxWorkers tasks run
+	if n := p.workers.Load(); n != 3 {
+		t.Errorf("workers got=%d want=3", n)
+	}
+	close(release)
+	for _, f := range fs {
+		f.Get(ctx)
+	}
+	clk.BlockUntil(2) // the idle timers of the extra workers
+	clk.Advance(time.Second)
+	p.Shutdown(ctx)
+	mu.Lock()
+	defer mu.Unlock()
+	peak := 0
+	for _, n := range active {
+		if n > peak {
+			peak = n
+		}
+	}
+	if peak != 3 {
+		t.Errorf("peak of active workers got=%d want=3", peak)
+	}
+}
+
+func TestPoolIdleTimeout(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	p := NewPool[int](ctx, PoolConfig{Workers: 1, MaxWorkers: 2, IdleTimeout: time.Second})
+	defer p.Shutdown(ctx)
+	started, release := make(chan int, 2), make(chan struct{})
+	f1 := p.Submit(ctx, gate(1, started, release))
+	f2 := p.Submit(ctx, gate(2, started, release))
+	<-started
+	<-started
+	close(release)
+	f1.Get(ctx)
+	f2.Get(ctx)
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	for p.workers.Load() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPoolHooks(t *testing.T) {
+	clk := chanstest.NewFakeClock()
+	ctx := clock.With(context.Background(), clk)
+	depths := make(chan int, 10)
+	latency := make(chan [2]time.Duration, 2)
+	p := NewPool[int](ctx, PoolConfig{
+		Workers:   1,
+		QueueSize: 2,
+		Hooks: PoolHooks{
+			QueueDepth:  func(n int) { depths <- n },
+			TaskLatency: func(wait, run time.Duration) { latency <- [2]time.Duration{wait, run} },
+		},
+	})
+	started, release := make(chan int, 1), make(chan struct{})
+	p.Submit(ctx, gate(1, started, release))
+	<-started
+	for len(depths) > 0 { // reported before the task started
+		<-depths
+	}
+	f := p.Submit(ctx, func(ctx context.Context) (int, error) {
+		clk.Advance(2 * time.Second)
+		return 2, nil
+	})
+	if n, _ := chanstest.Receive(t, depths, time.Second); n != 1 {
+		t.Errorf("queue depth got=%d want=1", n)
+	}
+	clk.Advance(3 * time.Second)
+	close(release)
+	f.Get(ctx)
+	if l, _ := chanstest.Receive(t, latency, time.Second); l != [2]time.Duration{0, 3 * time.Second} {
+		t.Errorf("latency of the first task got=%v want=[0s 3s]", l)
+	}
+	if l, _ := chanstest.Receive(t, latency, time.Second); l != [2]time.Duration{3 * time.Second, 2 * time.Second} {
+		t.Errorf("latency of the second task got=%v want=[3s 2s]", l)
+	}
+	if n, _ := chanstest.Receive(t, depths, time.Second); n != 0 {
+		t.Errorf("queue depth got=%d want=0", n)
+	}
+	p.Shutdown(ctx)
+}
@@ -0,0 +1,125 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+// Backoff returns the delay before retry number attempt (starting at 1),
+// prev is the delay before the previous retry, 0 for the first one.
+type Backoff func(attempt int, prev time.Duration) time.Duration
+
+// ConstantBackoff waits d before every retry
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int, time.Duration) time.Duration { return d }
+}
+
+// ExponentialBackoff waits base before the first retry and doubles the delay
+// for every following one, up to maxDelay. maxDelay <= 0 means no maximum.
+func ExponentialBackoff(base, maxDelay time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < math.MaxInt64/2; i++ {
+			if maxDelay > 0 && d >= maxDelay {
+				break
+			}
+			d *= 2
+		}
+		if maxDelay > 0 && d > maxDelay {
+			d = maxDelay
+		}
+		return d
+	}
+}
+
+// DecorrelatedJitterBackoff waits a random delay between base and three times
+// the previous delay, up to maxDelay. It spreads the retries of concurrent clients
+// while still growing roughly exponentially. maxDelay <= 0 means no maximum.
+func DecorrelatedJitterBackoff(base, maxDelay time.Duration) Backoff {
+	return func(_ int, prev time.Duration) time.Duration {
+		if prev < base {
+			prev = base
+		}
+		hi := time.Duration(math.MaxInt64)
+		if prev < math.MaxInt64/3 {
+			hi = 3 * prev
+		}
+		d := base
+		if hi > base {
+			d += rand.N(hi - base)
+		}
+		if maxDelay > 0 && d > maxDelay {
+			d = maxDelay
+		}
+		return d
+	}
+}
+
+// RetryPolicy tells Retry when and how long to wait before running a task again
+type RetryPolicy struct {
+	// Backoff returns the delay before each retry, nil means no delay
+	Backoff Backoff
+	// MaxAttempts is the maximum number of runs including the first one, unlimited if <= 0
+	MaxAttempts int
+	// MaxElapsed is the time after which no retry is started, unlimited if <= 0.
+	// A retry whose delay would end after MaxElapsed is not started either.
+	MaxElapsed time.Duration
+	// Retryable reports whether a task failing with err may be retried,
+	// nil means every error is retryable
+	Retryable func(err error) bool
+}
+
+// Retry returns a task running task until it succeeds or policy tells to stop,
+// in which case the last error is returned.
+// No retry is started once ctx is done, but a task failing because of its own
+// timeout, see WithTimeout, is retried. If ctx is done while waiting before
+// a retry, the returned error joins ctx.Err() and the last error.
+// The delays are measured with the clock of ctx, see clock.From.
+func Retry[T any](task Task[T], policy RetryPolicy) Task[T] {
+	return func(ctx context.Context) (T, error) {
+		clk := clock.From(ctx)
+		start := clk.Now()
+		var delay time.Duration
+		for attempt := 1; ; attempt++ {
+			res, err := task(ctx)
+			if err == nil || ctx.Err() != nil || !policy.retryable(err) ||
+				policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+				return res, err
+			}
+			if policy.Backoff != nil {
+				delay = policy.Backoff(attempt, delay)
+			}
+			if policy.MaxElapsed > 0 && clk.Now().Sub(start)+delay > policy.MaxElapsed {
+				return res, err
+			}
+			if serr := sleep(ctx, clk, delay); serr != nil {
+				return res, errors.Join(serr, err)
+			}
+		}
+	}
+}
+
+// retryable reports whether err may be retried according to p
+func (p RetryPolicy) retryable(err error) bool {
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// sleep waits for d on clock c. It returns ctx.Err() if ctx is done first.
+func sleep(ctx context.Context, c clock.Clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := c.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
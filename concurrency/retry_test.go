@@ -0,0 +1,157 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+// flaky returns a task failing with err until it ran n times, and the times it started
+func flaky(clk clock.Clock, n int, err error) (Task[int], *[]time.Time) {
+	var starts []time.Time
+	return func(ctx context.Context) (int, error) {
+		starts = append(starts, clk.Now())
+		if len(starts) <= n {
+			return 0, err
+		}
+		return len(starts), nil
+	}, &starts
+}
+
+// runRetry runs task on a new goroutine, advancing clk by each delay the task waits for
+func runRetry(t *testing.T, ctx context.Context, clk *chanstest.FakeClock, task Task[int], delays ...time.Duration) (int, error) {
+	t.Helper()
+	type result struct {
+		v   int
+		err error
+	}
+	c := make(chan result, 1)
+	go func() {
+		v, err := task(clock.With(ctx, clk))
+		c <- result{v, err}
+	}()
+	for _, d := range delays {
+		clk.BlockUntil(1)
+		clk.Advance(d)
+	}
+	r, _ := chanstest.Receive(t, c, time.Second)
+	return r.v, r.err
+}
+
+func TestBackoff(t *testing.T) {
+	s := time.Second
+	tests := []struct {
+		name    string
+		backoff Backoff
+		want    []time.Duration
+	}{
+		{"Constant", ConstantBackoff(s), []time.Duration{s, s, s}},
+		{"Exponential", ExponentialBackoff(s, 5*s), []time.Duration{s, 2 * s, 4 * s, 5 * s, 5 * s}},
+		{"ExponentialNoMax", ExponentialBackoff(s, 0), []time.Duration{s, 2 * s, 4 * s, 8 * s}},
+	}
+	for _, tt := range tests {
+		var prev time.Duration
+		for i, want := range tt.want {
+			prev = tt.backoff(i+1, prev)
+			if prev != want {
+				t.Errorf("%s attempt %d got=%v want=%v", tt.name, i+1, prev, want)
+			}
+		}
+	}
+	if d := ExponentialBackoff(time.Second, 0)(1000, 0); d <= 0 {
+		t.Errorf("Exponential must not overflow, got=%v", d)
+	}
+	jitter := DecorrelatedJitterBackoff(time.Second, 10*time.Second)
+	var prev time.Duration
+	for i := 1; i < 100; i++ {
+		d := jitter(i, prev)
+		if lo, hi := time.Second, 3*max(prev, time.Second); d < lo || d > hi || d > 10*time.Second {
+			t.Fatalf("DecorrelatedJitter attempt %d got=%v want in [%v, min(%v, 10s)]", i, d, lo, hi)
+		}
+		prev = d
+	}
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	errFoo := errors.New("foo")
+	clk := chanstest.NewFakeClock()
+	task, starts := flaky(clk, 3, errFoo)
+	retry := Retry(task, RetryPolicy{Backoff: ExponentialBackoff(time.Second, time.Minute)})
+	v, err := runRetry(t, ctx, clk, retry, time.Second, 2*time.Second, 4*time.Second)
+	if v != 4 || err != nil {
+		t.Errorf("got=(%d, %v) want=(4, nil)", v, err)
+	}
+	for i, d := range []time.Duration{0, time.Second, 3 * time.Second, 7 * time.Second} {
+		if got := (*starts)[i].Sub((*starts)[0]); got != d {
+			t.Errorf("attempt %d started after %v want %v", i+1, got, d)
+		}
+	}
+}
+
+func TestRetryLimits(t *testing.T) {
+	ctx := context.Background()
+	errFoo, errFatal := errors.New("foo"), errors.New("fatal")
+	clk := chanstest.NewFakeClock()
+	{ // max attempts
+		task, starts := flaky(clk, 10, errFoo)
+		retry := Retry(task, RetryPolicy{MaxAttempts: 3})
+		if _, err := runRetry(t, ctx, clk, retry); err != errFoo || len(*starts) != 3 {
+			t.Errorf("got=(%v, %d attempts) want=(%v, 3 attempts)", err, len(*starts), errFoo)
+		}
+	}
+	{ // max elapsed time
+		task, starts := flaky(clk, 10, errFoo)
+		retry := Retry(task, RetryPolicy{Backoff: ConstantBackoff(time.Second), MaxElapsed: 2500 * time.Millisecond})
+		if _, err := runRetry(t, ctx, clk, retry, time.Second, time.Second); err != errFoo || len(*starts) != 3 {
+			t.Errorf("got=(%v, %d attempts) want=(%v, 3 attempts)", err, len(*starts), errFoo)
+		}
+	}
+	{ // classifier
+		task, starts := flaky(clk, 10, errFatal)
+		retry := Retry(task, RetryPolicy{Retryable: func(err error) bool { return !errors.Is(err, errFatal) }})
+		if _, err := runRetry(t, ctx, clk, retry); err != errFatal || len(*starts) != 1 {
+			t.Errorf("got=(%v, %d attempts) want=(%v, 1 attempt)", err, len(*starts), errFatal)
+		}
+	}
+	{ // no retry once the context is done
+		ctx, cancel := context.WithCancel(ctx)
+		var n int
+		task := func(ctx context.Context) (int, error) {
+			n++
+			cancel()
+			return 0, ctx.Err()
+		}
+		if _, err := runRetry(t, ctx, clk, Retry(task, RetryPolicy{})); n != 1 || err != context.Canceled {
+			t.Errorf("got=(%v, %d attempts) want=(%v, 1 attempt)", err, n, context.Canceled)
+		}
+	}
+}
+
+func TestRetryCancel(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	errFoo := errors.New("foo")
+	clk := chanstest.NewFakeClock()
+	ctx, cancel := context.WithCancel(clock.With(context.Background(), clk))
+	task, starts := flaky(clk, 10, errFoo)
+	retry := Retry(task, RetryPolicy{Backoff: ConstantBackoff(time.Hour)})
+	c := make(chan error, 1)
+	go func() {
+		_, err := retry(ctx)
+		c <- err
+	}()
+	clk.BlockUntil(1) // sleeping before the first retry
+	cancel()
+	err, _ := chanstest.Receive(t, c, time.Second)
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, errFoo) || len(*starts) != 1 {
+		t.Errorf("got=(%v, %d attempts) want=(%v and %v, 1 attempt)", err, len(*starts), context.Canceled, errFoo)
+	}
+	if n := clk.Active(); n != 0 {
+		t.Errorf("the timer must be stopped, active timers got=%d", n)
+	}
+	check()
+}
@@ -0,0 +1,172 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+// WithTimeout returns a task running task with a context whose deadline is d from now,
+// like context.WithTimeout. If task fails once d elapsed because its context
+// was canceled, the returned error is context.DeadlineExceeded.
+// The timeout is measured with the clock of ctx, see clock.From.
+func WithTimeout[T any](task Task[T], d time.Duration) Task[T] {
+	return func(ctx context.Context) (T, error) {
+		tctx, cancel := withTimeout(ctx, d)
+		defer cancel()
+		res, err := task(tctx)
+		if errors.Is(err, context.Canceled) && errors.Is(context.Cause(tctx), context.DeadlineExceeded) {
+			return res, context.DeadlineExceeded
+		}
+		return res, err
+	}
+}
+
+// withTimeout is like context.WithTimeout with the clock of ctx
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	clk := clock.From(ctx)
+	if clk == clock.Real() {
+		return context.WithTimeout(ctx, d)
+	}
+	cctx, cancel := context.WithCancelCause(ctx)
+	timer := clk.NewTimer(d)
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-timer.C():
+			cancel(context.DeadlineExceeded)
+		case <-stop:
+		}
+	}()
+	var once sync.Once
+	return timeoutContext{cctx, clk.Now().Add(d)}, func() {
+		once.Do(func() {
+			timer.Stop()
+			close(stop)
+			cancel(nil)
+		})
+	}
+}
+
+// timeoutContext is a context canceled with cause context.DeadlineExceeded
+// by a timer of another clock than the real one
+type timeoutContext struct {
+	context.Context
+	deadline time.Time
+}
+
+// Deadline returns the time the timer fires, or the deadline of the parent if it is earlier
+func (c timeoutContext) Deadline() (time.Time, bool) {
+	if d, ok := c.Context.Deadline(); ok && d.Before(c.deadline) {
+		return d, true
+	}
+	return c.deadline, true
+}
+
+// Err returns context.DeadlineExceeded once the timer fired
+func (c timeoutContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && errors.Is(context.Cause(c.Context), context.DeadlineExceeded) {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// Hedge returns a task running task and starting a copy of it each time delay
+// elapsed without a success, up to maxHedges copies. When a run fails and no
+// other one is running, the next copy starts right away.
+// The result of the first run that succeeds is returned and the others are canceled.
+// If all of them fail, the returned error joins their errors in start order.
+// Like WhenAny, Hedge returns once all runs stopped, none of them outlives the call.
+// The delays are measured with the clock of ctx, see clock.From.
+func Hedge[T any](task Task[T], delay time.Duration, maxHedges int) Task[T] {
+	maxHedges = max(maxHedges, 0)
+	return func(ctx context.Context) (T, error) {
+		var zero T
+		hctx, cancel := context.WithCancel(ctx)
+		results := make(chan TaskResult[T], maxHedges+1)
+		errs := make([]error, maxHedges+1)
+		started, running := 0, 0
+		defer func() {
+			cancel()
+			for ; running > 0; running-- { // wait for the canceled runs
+				<-results
+			}
+		}()
+		launch := func() {
+			i := started
+			started++
+			running++
+			go func() {
+				res, err := task(hctx)
+				results <- TaskResult[T]{res, err, i}
+			}()
+		}
+		timer := clock.From(ctx).NewTimer(delay)
+		defer timer.Stop()
+		launch()
+		for {
+			select {
+			case r := <-results:
+				running--
+				if r.Err == nil {
+					return r.Result, nil
+				}
+				errs[r.Index] = r.Err
+				if running > 0 {
+					continue
+				}
+				if started > maxHedges {
+					return zero, errors.Join(errs...)
+				}
+				if err := ctx.Err(); err != nil {
+					return zero, err
+				}
+				launch()
+				if !timer.Stop() {
+					select {
+					case <-timer.C():
+					default:
+					}
+				}
+				timer.Reset(delay)
+			case <-timer.C():
+				if started <= maxHedges {
+					launch()
+					timer.Reset(delay)
+				}
+			case <-ctx.Done():
+				return zero, ctx.Err()
+			}
+		}
+	}
+}
+
+// Fallback returns a task running primary and then each of secondary in order
+// until one of them succeeds. No task is started once ctx is done, not even primary.
+// If all of them fail, the returned error joins their errors in order,
+// and ctx.Err() if ctx is done.
+func Fallback[T any](primary Task[T], secondary ...Task[T]) Task[T] {
+	tasks := append([]Task[T]{primary}, secondary...)
+	return func(ctx context.Context) (T, error) {
+		errs := make([]error, 0, len(tasks)+1)
+		for _, task := range tasks {
+			if ctx.Err() != nil {
+				break
+			}
+			res, err := task(ctx)
+			if err == nil {
+				return res, nil
+			}
+			errs = append(errs, err)
+		}
+		if err := ctx.Err(); err != nil && (len(errs) == 0 || !errors.Is(errs[len(errs)-1], err)) {
+			errs = append(errs, err)
+		}
+		var zero T
+		return zero, errors.Join(errs...)
+	}
+}
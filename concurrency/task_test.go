@@ -0,0 +1,241 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
+	"github.com/redouan-rhazouani/goboost/clock"
+)
+
+// blocking returns a task which blocks until its context is done,
+// started receives a value when the task starts.
+func blocking(started chan<- struct{}) Task[int] {
+	return func(ctx context.Context) (int, error) {
+		started <- struct{}{}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx := context.Background()
+	errFoo := errors.New("foo")
+	clk := chanstest.NewFakeClock()
+	started := make(chan struct{}, 1)
+	if _, err := runRetry(t, ctx, clk, WithTimeout(blocking(started), time.Second), time.Second); err != context.DeadlineExceeded {
+		t.Errorf("got=%v want=%v", err, context.DeadlineExceeded)
+	}
+	<-started
+	fast := func(context.Context) (int, error) { return 1, nil }
+	if v, err := runRetry(t, ctx, clk, WithTimeout(fast, time.Second)); v != 1 || err != nil {
+		t.Errorf("got=(%d, %v) want=(1, nil)", v, err)
+	}
+	failing := func(context.Context) (int, error) { return 0, errFoo }
+	if _, err := runRetry(t, ctx, clk, WithTimeout(failing, time.Second)); err != errFoo {
+		t.Errorf("got=%v want=%v", err, errFoo)
+	}
+	if n := clk.Active(); n != 0 {
+		t.Errorf("the timers must be stopped, active timers got=%d", n)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c := make(chan error, 1)
+	go func() {
+		_, err := WithTimeout(blocking(started), time.Second)(clock.With(ctx, clk))
+		c <- err
+	}()
+	<-started
+	cancel()
+	if err, _ := chanstest.Receive(t, c, time.Second); err != context.Canceled {
+		t.Errorf("canceled got=%v want=%v", err, context.Canceled)
+	}
+	check()
+}
+
+func TestWithTimeoutDeadline(t *testing.T) {
+	var deadline time.Time
+	var ok bool
+	task := func(ctx context.Context) (int, error) {
+		deadline, ok = ctx.Deadline()
+		return 0, nil
+	}
+	start := time.Now()
+	WithTimeout(task, time.Second)(context.Background())
+	if !ok || deadline.Before(start.Add(time.Second)) {
+		t.Errorf("real clock deadline got=(%v, %t) want=(after %v, true)", deadline, ok, start.Add(time.Second))
+	}
+	clk := chanstest.NewFakeClock()
+	WithTimeout(task, time.Second)(clock.With(context.Background(), clk))
+	if want := clk.Now().Add(time.Second); !ok || !deadline.Equal(want) {
+		t.Errorf("fake clock deadline got=(%v, %t) want=(%v, true)", deadline, ok, want)
+	}
+	started := make(chan struct{}, 1)
+	c := make(chan error, 1)
+	go func() {
+		_, err := WithTimeout(func(ctx context.Context) (int, error) {
+			started <- struct{}{}
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}, time.Second)(clock.With(context.Background(), clk))
+		c <- err
+	}()
+	<-started
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	if err, _ := chanstest.Receive(t, c, time.Second); err != context.DeadlineExceeded {
+		t.Errorf("ctx.Err() got=%v want=%v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestHedge(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx := context.Background()
+	clk := chanstest.NewFakeClock()
+	{ // the copy succeeds while the first run is slow
+		var n atomic.Int32
+		canceled := make(chan error, 1)
+		task := func(ctx context.Context) (int, error) {
+			i := int(n.Add(1))
+			if i == 1 {
+				<-ctx.Done()
+				canceled <- ctx.Err()
+				return 0, ctx.Err()
+			}
+			return i, nil
+		}
+		if v, err := runRetry(t, ctx, clk, Hedge(task, time.Second, 2), time.Second); v != 2 || err != nil {
+			t.Errorf("got=(%d, %v) want=(2, nil)", v, err)
+		}
+		select {
+		case err := <-canceled:
+			if err != context.Canceled {
+				t.Errorf("slow run got=%v want=%v", err, context.Canceled)
+			}
+		default:
+			t.Errorf("Hedge must wait for the canceled run")
+		}
+	}
+	{ // at most maxHedges copies
+		started, release := make(chan int, 4), make(chan struct{})
+		c := make(chan int, 1)
+		go func() {
+			v, _ := Hedge(gate(1, started, release), time.Second, 2)(clock.With(ctx, clk))
+			c <- v
+		}()
+		<-started
+		for range 2 {
+			clk.BlockUntil(1)
+			clk.Advance(time.Second)
+			<-started
+		}
+		clk.Advance(time.Second)
+		chanstest.AssertNoValue(t, started)
+		close(release)
+		if v, _ := chanstest.Receive(t, c, time.Second); v != 1 {
+			t.Errorf("got=%d want=1", v)
+		}
+	}
+	{ // failures start the next copy right away
+		var n atomic.Int32
+		task := func(context.Context) (int, error) {
+			return 0, fmt.Errorf("err%d", n.Add(1))
+		}
+		_, err := runRetry(t, ctx, clk, Hedge(task, time.Hour, 2))
+		if want := "err1\nerr2\nerr3"; err == nil || err.Error() != want {
+			t.Errorf("got=%q want=%q", err, want)
+		}
+	}
+	if n := clk.Active(); n != 0 {
+		t.Errorf("the timers must be stopped, active timers got=%d", n)
+	}
+	check()
+}
+
+func TestFallback(t *testing.T) {
+	ctx := context.Background()
+	errFoo, errBar := errors.New("foo"), errors.New("bar")
+	fail := func(err error) Task[int] {
+		return func(context.Context) (int, error) { return 0, err }
+	}
+	value := func(v int) Task[int] {
+		return func(context.Context) (int, error) { return v, nil }
+	}
+	if v, err := Fallback(fail(errFoo), value(2), value(3))(ctx); v != 2 || err != nil {
+		t.Errorf("got=(%d, %v) want=(2, nil)", v, err)
+	}
+	if v, err := Fallback(value(1))(ctx); v != 1 || err != nil {
+		t.Errorf("got=(%d, %v) want=(1, nil)", v, err)
+	}
+	if _, err := Fallback(fail(errFoo), fail(errBar))(ctx); !errors.Is(err, errFoo) || !errors.Is(err, errBar) {
+		t.Errorf("got=%v want=%v and %v", err, errFoo, errBar)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ran := false
+	cancel()
+	if _, err := Fallback(value(1))(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled before primary got=%v want=%v", err, context.Canceled)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	first := func(ctx context.Context) (int, error) {
+		cancel()
+		return 0, ctx.Err()
+	}
+	second := func(context.Context) (int, error) {
+		ran = true
+		return 2, nil
+	}
+	if _, err := Fallback(first, second)(ctx); !errors.Is(err, context.Canceled) || ran {
+		t.Errorf("got=(%v, second ran %t) want=(%v, false)", err, ran, context.Canceled)
+	}
+}
+
+func TestTaskComposition(t *testing.T) {
+	check := chanstest.LeakCheck(t)
+	ctx := context.Background()
+	clk := chanstest.NewFakeClock()
+	{ // the attempt timing out is retried
+		var n atomic.Int32
+		task := func(ctx context.Context) (int, error) {
+			if n.Add(1) == 1 {
+				<-ctx.Done()
+				return 0, ctx.Err()
+			}
+			return 2, nil
+		}
+		retry := Retry(WithTimeout(task, time.Second), RetryPolicy{MaxAttempts: 3})
+		if v, err := runRetry(t, ctx, clk, retry, time.Second); v != 2 || err != nil || n.Load() != 2 {
+			t.Errorf("got=(%d, %v, %d attempts) want=(2, nil, 2 attempts)", v, err, n.Load())
+		}
+	}
+	{ // hedged and fallback tasks run together
+		errFoo := errors.New("foo")
+		started := make(chan struct{}, 1)
+		hedged := Hedge(WithTimeout(blocking(started), time.Minute), time.Second, 0)
+		fallback := Fallback(
+			func(context.Context) (int, error) { return 0, errFoo },
+			func(context.Context) (int, error) { return 2, nil },
+		)
+		var res []int
+		c := make(chan error, 1)
+		go func() {
+			var err error
+			res, err = WhenAllResults(clock.With(ctx, clk), hedged, fallback)
+			c <- err
+		}()
+		<-started
+		clk.BlockUntil(2) // the hedge delay and the timeout
+		clk.Advance(time.Minute)
+		err, _ := chanstest.Receive(t, c, time.Second)
+		if !errors.Is(err, context.DeadlineExceeded) || len(res) != 2 || res[1] != 2 {
+			t.Errorf("got=(%v, %v) want=([0 2], %v)", res, err, context.DeadlineExceeded)
+		}
+	}
+	check()
+}
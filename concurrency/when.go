@@ -2,14 +2,17 @@ package concurrency
 
 import (
 	"context"
+	"errors"
 	"sync"
-	"sync/atomic"
 )
 
 // TaskResult holds the result of a computation
 type TaskResult[T any] struct {
 	Result T
 	Err    error
+	// Index is the position of the task that produced the result,
+	// -1 if the result does not come from a single task.
+	Index int
 }
 
 // Task represents a preemtive function
@@ -32,66 +35,179 @@ func RunGroup(fns ...func()) {
 // all tasks finish successfuly or at leat one of them fails.
 // The returned channel contains the result of each task.
 func WhenAll[T any](ctx context.Context, tasks ...Task[T]) <-chan TaskResult[T] {
-	N := len(tasks)
-	results := make(chan TaskResult[T], N)
-	go func(results chan<- TaskResult[T]) {
-		defer close(results)
+	return WhenAllLimit(ctx, 0, tasks...)
+}
 
-		ctx, cancel := context.WithCancel(ctx)
-		defer cancel()
-		// run tasks
-		errs := make(chan error, N)
-		for _, f := range tasks {
-			go func(f Task[T]) {
-				res, err := f(ctx)
-				results <- TaskResult[T]{res, err}
-				errs <- err
-			}(f)
-		}
-		// wait for all task to stop
-		for i := 0; i < N; i++ {
-			if err := <-errs; err != nil {
-				cancel() // it is safe to call cancel many times
-			}
-		}
-	}(results)
+// WhenAllLimit is like WhenAll but runs at most limit tasks at once,
+// the others wait until a running task returns. limit < 1 means no limit.
+// Once ctx is done or a task fails, the tasks not yet started are skipped
+// and reported with the context's error.
+func WhenAllLimit[T any](ctx context.Context, limit int, tasks ...Task[T]) <-chan TaskResult[T] {
+	results := make(chan TaskResult[T], len(tasks))
+	fs := spawn(ctx, limit, tasks)
+	AllOf(fs...) // cancels the other tasks once one fails
+	var wg sync.WaitGroup
+	wg.Add(len(fs))
+	for i, f := range fs {
+		f.subscribe(func() {
+			results <- TaskResult[T]{f.res, f.err, i}
+			wg.Done()
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
 	return results
 }
 
-// WhenAny runs tasks concurrently and returns when any task executes successfully
+// WhenAllResults runs tasks like WhenAll and waits for all of them to stop.
+// The result of each task is stored at the task's position in the returned slice.
+// The returned error joins the errors of all failed tasks in task order, nil if none failed.
+func WhenAllResults[T any](ctx context.Context, tasks ...Task[T]) ([]T, error) {
+	results := make([]T, len(tasks))
+	errs := make([]error, len(tasks))
+	for r := range WhenAll(ctx, tasks...) {
+		results[r.Index], errs[r.Index] = r.Result, r.Err
+	}
+	return results, errors.Join(errs...)
+}
+
+// WhenAny runs tasks concurrently and returns when any task executes successfully.
+// If all tasks fail, the result's error joins their errors in task order.
 func WhenAny[T any](ctx context.Context, funcs ...Task[T]) <-chan TaskResult[T] {
-	N := len(funcs)
+	return WhenAnyLimit(ctx, 0, funcs...)
+}
+
+// WhenAnyLimit is like WhenAny but runs at most limit tasks at once,
+// the others wait until a running task returns. limit < 1 means no limit.
+// Once ctx is done or a task succeeds, the tasks not yet started are skipped.
+func WhenAnyLimit[T any](ctx context.Context, limit int, funcs ...Task[T]) <-chan TaskResult[T] {
 	results := make(chan TaskResult[T], 1)
-	go func(results chan<- TaskResult[T]) {
+	fs := spawn(ctx, limit, funcs)
+	first := anyOf(fs) // cancels the other tasks once one succeeds
+	go func() {
 		defer close(results)
+		<-first.Done()
+		results <- first.res
+		// wait for all tasks to stop
+		for _, f := range fs {
+			<-f.Done()
+		}
+	}()
+	return results
+}
+
+// ErrNoQuorum is the error of WhenQuorum when fewer tasks than required succeed
+var ErrNoQuorum = errors.New("concurrency: quorum not reached")
+
+// WhenQuorum runs tasks concurrently and returns the results of the first n tasks
+// that succeed, in completion order, as soon as they did. The other tasks are canceled
+// and may still be running when WhenQuorum returns.
+// It fails as soon as fewer than n tasks can succeed, with an error joining
+// ErrNoQuorum and the errors of the failed tasks in task order. If ctx is done
+// first, the error joins ErrNoQuorum, ctx.Err() and the errors of the tasks
+// which failed before.
+// If n > len(tasks), it fails with ErrNoQuorum without running any task.
+func WhenQuorum[T any](ctx context.Context, n int, tasks ...Task[T]) ([]TaskResult[T], error) {
+	if n <= 0 {
+		return []TaskResult[T]{}, nil
+	}
+	if n > len(tasks) {
+		return nil, ErrNoQuorum
+	}
+	fs := spawn(ctx, 0, tasks)
+	q := quorumOf(fs, n)
+	select {
+	case <-q.Done():
+	case <-ctx.Done():
+		select {
+		case <-q.Done(): // completed at the same time, its result wins
+		default:
+			err := errors.Join(append([]error{ctx.Err()}, failures(ctx, fs)...)...)
+			q.Cancel()
+			return nil, errors.Join(ErrNoQuorum, err)
+		}
+	}
+	if q.err != nil {
+		return nil, errors.Join(ErrNoQuorum, q.err)
+	}
+	return q.res, nil
+}
 
-		ctx, cancel := context.WithCancel(ctx)
-		defer cancel()
+// failures returns the errors of the futures of fs which already failed, in order,
+// except the errors caused by ctx being done
+func failures[T any](ctx context.Context, fs []*Future[T]) []error {
+	var errs []error
+	for _, f := range fs {
+		select {
+		case <-f.Done():
+			if f.err != nil && !errors.Is(f.err, ctx.Err()) {
+				errs = append(errs, f.err)
+			}
+		default:
+		}
+	}
+	return errs
+}
 
-		var count int32
-		errs := make(chan error, N)
-		for _, fn := range funcs {
-			go func(f Task[T]) {
-				res, err := f(ctx)
-				if err == nil && atomic.CompareAndSwapInt32(&count, 0, 1) {
-					results <- TaskResult[T]{res, err}
-					cancel() // we have a winner
-				}
-				errs <- err
-			}(fn)
+// spawn returns the futures of tasks and starts them in new goroutines,
+// at most limit at once if limit > 0.
+// Once ctx is done, the tasks not yet started are completed with ctx.Err().
+func spawn[T any](ctx context.Context, limit int, tasks []Task[T]) []*Future[T] {
+	fs := make([]*Future[T], len(tasks))
+	starts := make([]func() func(), len(tasks))
+	for i, task := range tasks {
+		fs[i], starts[i] = prepare(ctx, task)
+	}
+	if limit < 1 || limit >= len(tasks) {
+		// claim all tasks before any of them can fail and cancel the others
+		runs := make([]func(), 0, len(tasks))
+		for _, start := range starts {
+			if run := start(); run != nil {
+				runs = append(runs, run)
+			}
+		}
+		for _, run := range runs {
+			go run()
 		}
-		// wait for all goroutines to stop
-		var firstErr error
-		for i := 0; i < N; i++ {
-			if err := <-errs; err != nil && firstErr == nil {
-				firstErr = err
+		return fs
+	}
+	sem := make(chan struct{}, limit)
+	go func() {
+		for _, start := range starts {
+			if !acquire(ctx, sem) {
+				start() // completes with ctx.Err()
+				continue
+			}
+			run := start()
+			if run == nil { // canceled
+				<-sem
+				continue
 			}
+			go func() {
+				run()
+				<-sem // after run, whose result may cancel the remaining tasks
+			}()
 		}
+	}()
+	return fs
+}
 
-		// all tasks were not successful
-		if atomic.LoadInt32(&count) < 1 {
-			results <- TaskResult[T]{Err: firstErr}
+// acquire takes a slot of sem, waiting until one is free.
+// It returns false if ctx is done first.
+func acquire(ctx context.Context, sem chan struct{}) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	select {
+	case sem <- struct{}{}:
+		if ctx.Err() != nil { // both were ready
+			<-sem
+			return false
 		}
-	}(results)
-	return results
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
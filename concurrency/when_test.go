@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/redouan-rhazouani/goboost/chans/chanstest"
 )
 
 func TestRunGroup(t *testing.T) {
@@ -23,26 +26,16 @@ func TestRunGroup(t *testing.T) {
 func TestWhenAllZero(t *testing.T) {
 	ctx := context.Background()
 	res := WhenAll[int](ctx)
-	select {
-	case r := <-res:
-		if r.Result != 0 || r.Err != nil {
-			t.Errorf("Result got=%v want={0, nil}", r)
-		}
-	case <-time.After(time.Millisecond * 100):
-		t.Errorf("timeout")
+	if r, _ := chanstest.Receive(t, res, 100*time.Millisecond); r.Result != 0 || r.Err != nil {
+		t.Errorf("Result got=%v want={0, nil}", r)
 	}
 }
 
 func TestWhenAnyZero(t *testing.T) {
 	ctx := context.Background()
 	res := WhenAny[int](ctx)
-	select {
-	case r := <-res:
-		if r.Result != 0 || r.Err != nil {
-			t.Errorf("Result got=%v want={0, nil}", r)
-		}
-	case <-time.After(time.Millisecond * 100):
-		t.Errorf("timeout")
+	if r, _ := chanstest.Receive(t, res, 100*time.Millisecond); r.Result != 0 || r.Err != nil {
+		t.Errorf("Result got=%v want={0, nil}", r)
 	}
 }
 
@@ -51,36 +44,21 @@ func TestWhenAllOne(t *testing.T) {
 	ctx := context.Background()
 	task := newTask(ctx, "Task-1", 1, time.Millisecond, nil)
 	c := WhenAll(ctx, task)
-	select {
-	case r := <-c:
-		if r.Result != 1 || r.Err != nil {
-			t.Errorf("Result got=%v want={0, nil}", r)
-		}
-	case <-time.After(time.Millisecond * 100):
-		t.Errorf("timeout")
+	if r, _ := chanstest.Receive(t, c, 100*time.Millisecond); r.Result != 1 || r.Err != nil {
+		t.Errorf("Result got=%v want={0, nil}", r)
 	}
 
 	task = newTask(ctx, "Task-2", 1, time.Millisecond, anyError)
 	c = WhenAll(ctx, task)
-	select {
-	case r := <-c:
-		if r.Result != 0 || !errors.Is(r.Err, anyError) {
-			t.Errorf("Result got=%v want={0, %v}", r, anyError)
-		}
-	case <-time.After(time.Millisecond * 100):
-		t.Errorf("timeout")
+	if r, _ := chanstest.Receive(t, c, 100*time.Millisecond); r.Result != 0 || !errors.Is(r.Err, anyError) {
+		t.Errorf("Result got=%v want={0, %v}", r, anyError)
 	}
 	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
 	defer cancel()
 	task = newTask(ctx, "Task-2", 1, time.Second, nil)
 	c = WhenAll(ctx, task)
-	select {
-	case r := <-c:
-		if r.Result != 0 || !errors.Is(r.Err, context.DeadlineExceeded) {
-			t.Errorf("Result got=%v want={0, %v}", r, anyError)
-		}
-	case <-time.After(time.Millisecond * 100):
-		t.Errorf("timeout")
+	if r, _ := chanstest.Receive(t, c, 100*time.Millisecond); r.Result != 0 || !errors.Is(r.Err, context.DeadlineExceeded) {
+		t.Errorf("Result got=%v want={0, %v}", r, anyError)
 	}
 
 }
@@ -90,36 +68,21 @@ func TestWhenAnyOne(t *testing.T) {
 	ctx := context.Background()
 	task := newTask(ctx, "Task-1", 1, time.Millisecond, nil)
 	c := WhenAny(ctx, task)
-	select {
-	case r := <-c:
-		if r.Result != 1 || r.Err != nil {
-			t.Errorf("Result got=%v want={0, nil}", r)
-		}
-	case <-time.After(time.Millisecond * 100):
-		t.Errorf("timeout")
+	if r, _ := chanstest.Receive(t, c, 100*time.Millisecond); r.Result != 1 || r.Err != nil {
+		t.Errorf("Result got=%v want={0, nil}", r)
 	}
 
 	task = newTask(ctx, "Task-2", 1, time.Millisecond, anyError)
 	c = WhenAny(ctx, task)
-	select {
-	case r := <-c:
-		if r.Result != 0 || !errors.Is(r.Err, anyError) {
-			t.Errorf("Result got=%v want={0, %v}", r, anyError)
-		}
-	case <-time.After(time.Millisecond * 100):
-		t.Errorf("timeout")
+	if r, _ := chanstest.Receive(t, c, 100*time.Millisecond); r.Result != 0 || !errors.Is(r.Err, anyError) {
+		t.Errorf("Result got=%v want={0, %v}", r, anyError)
 	}
 	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
 	defer cancel()
 	task = newTask(ctx, "Task-2", 1, time.Second, nil)
 	c = WhenAny(ctx, task)
-	select {
-	case r := <-c:
-		if r.Result != 0 || !errors.Is(r.Err, context.DeadlineExceeded) {
-			t.Errorf("Result got=%v want={0, %v}", r, anyError)
-		}
-	case <-time.After(time.Millisecond * 100):
-		t.Errorf("timeout")
+	if r, _ := chanstest.Receive(t, c, 100*time.Millisecond); r.Result != 0 || !errors.Is(r.Err, context.DeadlineExceeded) {
+		t.Errorf("Result got=%v want={0, %v}", r, anyError)
 	}
 
 }
@@ -201,6 +164,177 @@ func TestWhenAllMany(t *testing.T) {
 	}
 }
 
+func TestWhenAllIndex(t *testing.T) {
+	ctx := context.Background()
+	tasks := []Task[int]{
+		newTask(ctx, "Task-1", 10, 3*time.Millisecond, nil),
+		newTask(ctx, "Task-2", 20, time.Millisecond, nil),
+		newTask(ctx, "Task-3", 30, 2*time.Millisecond, nil),
+	}
+	seen := make([]bool, len(tasks))
+	for r := range WhenAll(ctx, tasks...) {
+		if r.Result != 10*(r.Index+1) {
+			t.Errorf("Index got=%d for result %d", r.Index, r.Result)
+			continue
+		}
+		seen[r.Index] = true
+	}
+	for i, ok := range seen {
+		if !ok {
+			t.Errorf("no result for task %d", i)
+		}
+	}
+	errFoo := errors.New("foo")
+	c := WhenAny(ctx, newTask(ctx, "Task-1", 10, 0, errFoo), tasks[1], newTask(ctx, "Task-3", 30, 0, errFoo))
+	r, _ := chanstest.Receive(t, c, 100*time.Millisecond)
+	if r.Index != 1 || r.Result != 20 {
+		t.Errorf("WhenAny got=%v want={20, nil, 1}", r)
+	}
+	r, _ = chanstest.Receive(t, WhenAny(ctx, newTask(ctx, "Task-1", 1, 0, errFoo)), 100*time.Millisecond)
+	if r.Index != -1 || !errors.Is(r.Err, errFoo) {
+		t.Errorf("WhenAny got=%v want={0, %v, -1}", r, errFoo)
+	}
+}
+
+func TestWhenAllResults(t *testing.T) {
+	errFoo, errBar := errors.New("foo"), errors.New("bar")
+	ctx := context.Background()
+	res, err := WhenAllResults(ctx,
+		newTask(ctx, "Task-1", 1, 3*time.Millisecond, nil),
+		newTask(ctx, "Task-2", 2, time.Millisecond, nil),
+		newTask(ctx, "Task-3", 3, 2*time.Millisecond, nil),
+	)
+	if want := []int{1, 2, 3}; err != nil || fmt.Sprint(res) != fmt.Sprint(want) {
+		t.Errorf("got=(%v, %v) want=(%v, nil)", res, err, want)
+	}
+	res, err = WhenAllResults[int](ctx)
+	if len(res) != 0 || err != nil {
+		t.Errorf("got=(%v, %v) want=([], nil)", res, err)
+	}
+	fail := func(err error) Task[int] {
+		return func(context.Context) (int, error) { return 0, err } // ignores cancellation
+	}
+	res, err = WhenAllResults(ctx, fail(errFoo), newTask(ctx, "Task-2", 2, 0, nil), fail(errBar))
+	if !errors.Is(err, errFoo) || !errors.Is(err, errBar) {
+		t.Errorf("err got=%v want both %v and %v", err, errFoo, errBar)
+	}
+	if res[0] != 0 || res[2] != 0 {
+		t.Errorf("results of failed tasks got=%v want zero values", res)
+	}
+	{ // cancellation stops the remaining tasks
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		res, err := WhenAllResults(ctx, newTask(ctx, "Task-1", 1, time.Second, nil))
+		if res[0] != 0 || !errors.Is(err, context.Canceled) {
+			t.Errorf("got=(%v, %v) want=([0], %v)", res, err, context.Canceled)
+		}
+	}
+}
+
+// limitTasks returns n tasks returning their index and the maximum number of tasks seen running at once
+func limitTasks(n int) ([]Task[int], *int32) {
+	var running, peak int32
+	tasks := make([]Task[int], n)
+	for i := range tasks {
+		tasks[i] = func(ctx context.Context) (int, error) {
+			r := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for p := atomic.LoadInt32(&peak); r > p && !atomic.CompareAndSwapInt32(&peak, p, r); p = atomic.LoadInt32(&peak) {
+			}
+			time.Sleep(100 * time.Microsecond)
+			return i, nil
+		}
+	}
+	return tasks, &peak
+}
+
+func TestWhenAllLimit(t *testing.T) {
+	ctx := context.Background()
+	for _, limit := range []int{1, 3, 10} {
+		tasks, peak := limitTasks(50)
+		res, err := WhenAllResults(ctx, tasks...) // no limit
+		if err != nil {
+			t.Fatal(err)
+		}
+		*peak = 0
+		n := 0
+		for r := range WhenAllLimit(ctx, limit, tasks...) {
+			if r.Err != nil || r.Result != res[r.Index] {
+				t.Errorf("limit %d got=%v", limit, r)
+			}
+			n++
+		}
+		if n != len(tasks) {
+			t.Errorf("limit %d number of results got=%d want=%d", limit, n, len(tasks))
+		}
+		if p := atomic.LoadInt32(peak); p > int32(limit) {
+			t.Errorf("limit %d exceeded, %d tasks were running at once", limit, p)
+		}
+	}
+}
+
+func TestWhenAnyLimit(t *testing.T) {
+	ctx := context.Background()
+	errFoo := errors.New("foo")
+	tasks, peak := limitTasks(20)
+	for i := range tasks[:19] {
+		tasks[i] = newTask(ctx, "Task", i, 0, errFoo)
+	}
+	r, _ := chanstest.Receive(t, WhenAnyLimit(ctx, 2, tasks...), time.Second)
+	if r.Err != nil || r.Index != 19 || r.Result != 19 {
+		t.Errorf("got=%v want={19, nil, 19}", r)
+	}
+	if p := atomic.LoadInt32(peak); p > 2 {
+		t.Errorf("limit exceeded, %d tasks were running at once", p)
+	}
+}
+
+func TestLimitSkipsUnstarted(t *testing.T) {
+	var started int32
+	running := make(chan struct{}, 5)
+	block := func(ctx context.Context) (int, error) {
+		atomic.AddInt32(&started, 1)
+		running <- struct{}{}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	{ // cancellation
+		ctx, cancel := context.WithCancel(context.Background())
+		c := WhenAllLimit(ctx, 2, block, block, block, block, block)
+		for range 2 { // both slots are taken
+			chanstest.Receive(t, running, time.Second)
+		}
+		cancel()
+		n := 0
+		for r := range c {
+			if !errors.Is(r.Err, context.Canceled) {
+				t.Errorf("got=%v want=%v", r.Err, context.Canceled)
+			}
+			n++
+		}
+		if n != 5 {
+			t.Errorf("number of results got=%d want=5", n)
+		}
+		if s := atomic.LoadInt32(&started); s != 2 {
+			t.Errorf("started tasks got=%d want=2", s)
+		}
+	}
+	{ // a winner stops WhenAnyLimit from starting more tasks
+		atomic.StoreInt32(&started, 0)
+		ok := func(ctx context.Context) (int, error) {
+			atomic.AddInt32(&started, 1)
+			return 1, nil
+		}
+		r, _ := chanstest.Receive(t, WhenAnyLimit(context.Background(), 1, ok, ok, ok), time.Second)
+		if r.Result != 1 || r.Index != 0 {
+			t.Errorf("got=%v want={1, nil, 0}", r)
+		}
+		if s := atomic.LoadInt32(&started); s != 1 {
+			t.Errorf("started tasks got=%d want=1", s)
+		}
+	}
+}
+
 func TestWhenAnyMany(t *testing.T) {
 	errFoo := errors.New("foo")
 	ctx := context.Background()
@@ -276,6 +410,105 @@ func TestWhenAnyMany(t *testing.T) {
 	}
 }
 
+func TestWhenAnyAllFailed(t *testing.T) {
+	ctx := context.Background()
+	errFoo, errBar := errors.New("foo"), errors.New("bar")
+	c := WhenAny(ctx, newTask(ctx, "Task-1", 1, 0, errFoo), newTask(ctx, "Task-2", 2, time.Millisecond, errBar))
+	r, _ := chanstest.Receive(t, c, time.Second)
+	if !errors.Is(r.Err, errFoo) || !errors.Is(r.Err, errBar) {
+		t.Errorf("got=%v want both %v and %v", r.Err, errFoo, errBar)
+	}
+}
+
+func TestWhenQuorum(t *testing.T) {
+	ctx := context.Background()
+	errFoo, errBar := errors.New("foo"), errors.New("bar")
+	{
+		slow := make(chan error, 1)
+		res, err := WhenQuorum(ctx, 2,
+			newTask(ctx, "Task-1", 1, 20*time.Millisecond, nil),
+			func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				slow <- ctx.Err()
+				return 0, ctx.Err()
+			},
+			newTask(ctx, "Task-3", 3, 0, nil),
+		)
+		if err != nil || len(res) != 2 || res[0] != (TaskResult[int]{3, nil, 2}) || res[1] != (TaskResult[int]{1, nil, 0}) {
+			t.Errorf("got=(%v, %v) want=([{3 <nil> 2} {1 <nil> 0}], nil)", res, err)
+		}
+		if err, _ := chanstest.Receive(t, slow, time.Second); !errors.Is(err, context.Canceled) {
+			t.Errorf("the remaining task got=%v want=%v", err, context.Canceled)
+		}
+	}
+	{ // fails as soon as the quorum cannot be reached
+		start := time.Now()
+		res, err := WhenQuorum(ctx, 2,
+			newTask(ctx, "Task-1", 1, 0, errFoo),
+			newTask(ctx, "Task-2", 2, time.Minute, nil),
+			newTask(ctx, "Task-3", 3, time.Millisecond, errBar),
+		)
+		if res != nil || !errors.Is(err, ErrNoQuorum) || !errors.Is(err, errFoo) || !errors.Is(err, errBar) {
+			t.Errorf("got=(%v, %v) want=(nil, %v, %v and %v)", res, err, ErrNoQuorum, errFoo, errBar)
+		}
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("WhenQuorum returned after %v, it must fail fast", d)
+		}
+	}
+	{
+		if res, err := WhenQuorum[int](ctx, 0); len(res) != 0 || err != nil {
+			t.Errorf("n=0 got=(%v, %v) want=([], nil)", res, err)
+		}
+		if _, err := WhenQuorum(ctx, 2, newTask(ctx, "Task-1", 1, 0, nil)); err != ErrNoQuorum {
+			t.Errorf("n > len(tasks) got=%v want=%v", err, ErrNoQuorum)
+		}
+	}
+	{ // cancellation
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := WhenQuorum(ctx, 1,
+			newTask(ctx, "Task-1", 1, 0, errFoo),
+			newTask(ctx, "Task-2", 2, time.Minute, nil),
+		)
+		if !errors.Is(err, ErrNoQuorum) || !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errFoo) {
+			t.Errorf("got=%v want=%v, %v and %v", err, ErrNoQuorum, context.DeadlineExceeded, errFoo)
+		}
+	}
+	{ // a task ignoring cancellation does not delay the quorum
+		release := make(chan struct{})
+		defer close(release)
+		c := make(chan error, 1)
+		go func() {
+			_, err := WhenQuorum(ctx, 1,
+				newTask(ctx, "Task-1", 1, 0, nil),
+				func(context.Context) (int, error) {
+					<-release
+					return 0, nil
+				},
+			)
+			c <- err
+		}()
+		if err, _ := chanstest.Receive(t, c, time.Second); err != nil {
+			t.Errorf("got=%v want=nil", err)
+		}
+	}
+	for range 100 { // ctx canceled right after the quorum is reached
+		ctx, cancel := context.WithCancel(ctx)
+		res, err := WhenQuorum(ctx, 1,
+			newTask(ctx, "Task-1", 1, 0, nil),
+			func(ctx context.Context) (int, error) {
+				<-ctx.Done() // canceled once the quorum is reached
+				cancel()
+				return 0, ctx.Err()
+			},
+		)
+		cancel()
+		if err != nil || len(res) != 1 || res[0].Result != 1 {
+			t.Fatalf("got=(%v, %v) want=([{1 <nil> 0}], nil)", res, err)
+		}
+	}
+}
+
 func newTask(ctx context.Context, name string, ret int, dur time.Duration, err error) Task[int] {
 	return func(ctx context.Context) (int, error) {
 		select {
@@ -5,8 +5,15 @@
 //		// do something with e.Value
 //	}
 //
+// or range over its values:
+//	for v := range l.Values() {
+//		// do something with v
+//	}
+//
 package forward_list
 
+import "github.com/redouan-rhazouani/goboost/iterator"
+
 type Element[T any] struct {
 	// Next pointer to the next element in the singly-linked list.
 	next *Element[T]
@@ -120,6 +127,32 @@ func (l ForwardList[T]) Do(f func(T)) {
 	}
 }
 
+// All returns an iterator over the positions and values of l from front to back
+// It's not ok to call delete method while iterating
+func (l *ForwardList[T]) All() iterator.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for e := l.Front(); e != nil; e = e.Next() {
+			if !yield(i, e.Value) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Values returns an iterator over the values of l from front to back
+// It's not ok to call delete method while iterating
+func (l *ForwardList[T]) Values() iterator.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := l.Front(); e != nil; e = e.Next() {
+			if !yield(e.Value) {
+				return
+			}
+		}
+	}
+}
+
 // // Remove element e from l if e is an element of list l.
 // // It returns e.next or nil.
 // // The element must not be nil
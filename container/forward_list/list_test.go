@@ -2,6 +2,8 @@ package forward_list
 
 import (
 	"testing"
+
+	"github.com/redouan-rhazouani/goboost/iterator"
 )
 
 func checkListLen[T any](t *testing.T, l *ForwardList[T], len int) bool {
@@ -273,3 +275,33 @@ func TestDo(t *testing.T) {
 		t.Errorf("sum got=%d want 6", sum)
 	}
 }
+
+func TestIterators(t *testing.T) {
+	var _ iterator.Indexed[int] = New[int]()
+	l := New[int]()
+	for _, v := range []int{3, 1, 2} {
+		l.PushBack(v)
+	}
+	want := []int{3, 1, 2}
+	var got []int
+	for i, v := range l.All() {
+		if i != len(got) {
+			t.Errorf("index got=%d want=%d", i, len(got))
+		}
+		got = append(got, v)
+	}
+	checkList(t, l, got)
+	got = got[:0]
+	for v := range l.Values() {
+		got = append(got, v)
+		if v == 1 {
+			break
+		}
+	}
+	if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("break got=%v want=%v", got, want[:2])
+	}
+	for range New[int]().Values() {
+		t.Errorf("empty list must not yield")
+	}
+}
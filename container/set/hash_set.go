@@ -1,6 +1,8 @@
 // Package set implements hash sets of any comparable type
 package set
 
+import "github.com/redouan-rhazouani/goboost/iterator"
+
 // Set is an unordered collection with unique elements.
 //
 // Set requires that the elements statisfy the comparable constraint.
@@ -76,6 +78,18 @@ func (s Set[T]) Do(f func(T)) {
 	}
 }
 
+// Values returns an iterator over the elements of s in unspecified order
+// It's ok to call s.Delete(v) while iterating
+func (s Set[T]) Values() iterator.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range s {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
 // Update set s, adding elements from set o
 func (s Set[T]) Update(o Set[T]) {
 	for v := range o {
@@ -2,6 +2,8 @@ package set
 
 import (
 	"testing"
+
+	"github.com/redouan-rhazouani/goboost/iterator"
 )
 
 func checkSetLen[T comparable](t *testing.T, s Set[T], len int) bool {
@@ -280,3 +282,24 @@ func TestForEach(t *testing.T) {
 	}
 	checkSet(t, xs, xs.Slice())
 }
+
+func TestValues(t *testing.T) {
+	var _ iterator.Iterable[int] = Set[int]{}
+	xs := FromSlice([]int{1, 2, 3})
+	got := Make[int]()
+	for v := range xs.Values() {
+		got.Add(v)
+	}
+	if !got.Equal(xs) {
+		t.Errorf("got=%v want=%v", got, xs)
+	}
+	n := 0
+	for v := range xs.Values() {
+		xs.Delete(v)
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	checkSetLen(t, xs, 1)
+}
@@ -1,6 +1,8 @@
 // Package vector implements a contiguous growable array type
 package vector
 
+import "github.com/redouan-rhazouani/goboost/iterator"
+
 // Vector is a wrapper around a generic slice.
 type Vector[T any] []T
 
@@ -55,6 +57,28 @@ func (vec Vector[T]) LastIndexFunc(f func(T) bool) int {
 	return -1
 }
 
+// All returns an iterator over the index-value pairs of vec in order
+func (vec Vector[T]) All() iterator.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, x := range vec {
+			if !yield(i, x) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the elements of vec in order
+func (vec Vector[T]) Values() iterator.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, x := range vec {
+			if !yield(x) {
+				return
+			}
+		}
+	}
+}
+
 // Copy returns a shallow copy of vec
 func (vec Vector[T]) Copy() Vector[T] {
 	buf := make(Vector[T], len(vec))
@@ -2,6 +2,8 @@ package vector
 
 import (
 	"testing"
+
+	"github.com/redouan-rhazouani/goboost/iterator"
 )
 
 func eq(v int) func(int) bool {
@@ -295,3 +297,28 @@ func TestRemove(t *testing.T) {
 		}
 	}
 }
+
+func TestIterators(t *testing.T) {
+	var _ iterator.Indexed[int] = Vector[int]{}
+	vec := Vector[int]{3, 1, 2}
+	var got Vector[int]
+	for i, v := range vec.All() {
+		if i != got.Len() {
+			t.Errorf("index got=%d want=%d", i, got.Len())
+		}
+		got.Push(v)
+	}
+	if !Equal(got, vec) {
+		t.Errorf("All got=%v want=%v", got, vec)
+	}
+	got.Clear()
+	for v := range vec.Values() {
+		got.Push(v)
+		if v == 1 {
+			break
+		}
+	}
+	if want := vec[:2]; !Equal(got, want) {
+		t.Errorf("Values got=%v want=%v", got, want)
+	}
+}
@@ -0,0 +1,101 @@
+// Package iterator defines the iterator protocol shared by the containers
+//
+// Iterators are push style: a Seq calls yield for each value until yield
+// returns false or the values are exhausted, so they can be used directly
+// in a range loop:
+//
+//	for v := range vec.Values() {
+//		// do something with v
+//	}
+//
+// Seq and Seq2 are aliases of the standard library types, any function
+// accepting an iter.Seq accepts them and vice versa.
+// Pull converts a push iterator into a pull iterator.
+package iterator
+
+import "iter"
+
+// Seq is an iterator over sequences of individual values
+type Seq[V any] = iter.Seq[V]
+
+// Seq2 is an iterator over sequences of pairs of values, usually key-value or index-value pairs
+type Seq2[K, V any] = iter.Seq2[K, V]
+
+// Iterable is implemented by containers which can iterate over their values
+type Iterable[V any] interface {
+	Values() Seq[V]
+}
+
+// Indexed is implemented by containers which can iterate over their values and positions
+type Indexed[V any] interface {
+	Iterable[V]
+	All() Seq2[int, V]
+}
+
+// Of returns an iterator over vs
+func Of[V any](vs ...V) Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range vs {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Empty returns an iterator over no values
+func Empty[V any]() Seq[V] {
+	return func(yield func(V) bool) {}
+}
+
+// Iterator is a pull iterator, see Pull
+type Iterator[V any] struct {
+	next func() (V, bool)
+	stop func()
+}
+
+// Pull converts the push iterator seq into a pull iterator.
+// Stop must be called when the caller is done with the iterator
+// unless Next already returned false.
+func Pull[V any](seq Seq[V]) *Iterator[V] {
+	next, stop := iter.Pull(seq)
+	return &Iterator[V]{next, stop}
+}
+
+// Next returns the next value and true, or the zero value and false
+// if the sequence is exhausted or Stop has been called
+func (it *Iterator[V]) Next() (V, bool) {
+	return it.next()
+}
+
+// Stop ends the iteration, releasing its resources.
+// It's ok to call Stop multiple times.
+func (it *Iterator[V]) Stop() {
+	it.stop()
+}
+
+// Iterator2 is a pull iterator over pairs of values, see Pull2
+type Iterator2[K, V any] struct {
+	next func() (K, V, bool)
+	stop func()
+}
+
+// Pull2 converts the push iterator seq into a pull iterator.
+// Stop must be called when the caller is done with the iterator
+// unless Next already returned false.
+func Pull2[K, V any](seq Seq2[K, V]) *Iterator2[K, V] {
+	next, stop := iter.Pull2(seq)
+	return &Iterator2[K, V]{next, stop}
+}
+
+// Next returns the next pair and true, or zero values and false
+// if the sequence is exhausted or Stop has been called
+func (it *Iterator2[K, V]) Next() (K, V, bool) {
+	return it.next()
+}
+
+// Stop ends the iteration, releasing its resources.
+// It's ok to call Stop multiple times.
+func (it *Iterator2[K, V]) Stop() {
+	it.stop()
+}
@@ -0,0 +1,76 @@
+package iterator
+
+import (
+	"testing"
+)
+
+func collect[V any](seq Seq[V]) []V {
+	a := []V{}
+	for v := range seq {
+		a = append(a, v)
+	}
+	return a
+}
+
+func TestOf(t *testing.T) {
+	if got := collect(Of(1, 2, 3)); len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Errorf("got=%v want=[1 2 3]", got)
+	}
+	if got := collect(Empty[int]()); len(got) != 0 {
+		t.Errorf("got=%v want=[]", got)
+	}
+	n := 0
+	for range Of(1, 2, 3) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("break got=%d want=1", n)
+	}
+}
+
+func TestPull(t *testing.T) {
+	it := Pull(Of(1, 2))
+	for _, want := range []int{1, 2} {
+		if v, ok := it.Next(); !ok || v != want {
+			t.Errorf("Next got=(%d, %v) want=(%d, true)", v, ok, want)
+		}
+	}
+	if v, ok := it.Next(); ok {
+		t.Errorf("Next got=(%d, %v) want=(0, false)", v, ok)
+	}
+	it.Stop()
+
+	stopped := false
+	seq := func(yield func(int) bool) {
+		defer func() { stopped = true }()
+		for i := 0; yield(i); i++ {
+		}
+	}
+	it = Pull(seq)
+	it.Next()
+	it.Stop()
+	it.Stop()
+	if !stopped {
+		t.Errorf("Stop must end the push iterator")
+	}
+	if _, ok := it.Next(); ok {
+		t.Errorf("Next after Stop must return false")
+	}
+}
+
+func TestPull2(t *testing.T) {
+	seq := func(yield func(int, string) bool) {
+		_ = yield(0, "a") && yield(1, "b")
+	}
+	it := Pull2(Seq2[int, string](seq))
+	defer it.Stop()
+	for i, want := range []string{"a", "b"} {
+		if k, v, ok := it.Next(); !ok || k != i || v != want {
+			t.Errorf("Next got=(%d, %s, %v) want=(%d, %s, true)", k, v, ok, i, want)
+		}
+	}
+	if _, _, ok := it.Next(); ok {
+		t.Errorf("Next must return false after the last pair")
+	}
+}
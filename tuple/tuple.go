@@ -0,0 +1,47 @@
+// Package tuple implements generic tuples of heterogeneous values
+package tuple
+
+import "fmt"
+
+// Pair holds two values of possibly different types
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// MakePair returns the pair (a, b)
+func MakePair[A, B any](a A, b B) Pair[A, B] {
+	return Pair[A, B]{a, b}
+}
+
+// Values returns the elements of pair p
+func (p Pair[A, B]) Values() (A, B) {
+	return p.First, p.Second
+}
+
+// String returns p formatted as (First, Second)
+func (p Pair[A, B]) String() string {
+	return fmt.Sprintf("(%v, %v)", p.First, p.Second)
+}
+
+// Triple holds three values of possibly different types
+type Triple[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// MakeTriple returns the triple (a, b, c)
+func MakeTriple[A, B, C any](a A, b B, c C) Triple[A, B, C] {
+	return Triple[A, B, C]{a, b, c}
+}
+
+// Values returns the elements of triple t
+func (t Triple[A, B, C]) Values() (A, B, C) {
+	return t.First, t.Second, t.Third
+}
+
+// String returns t formatted as (First, Second, Third)
+func (t Triple[A, B, C]) String() string {
+	return fmt.Sprintf("(%v, %v, %v)", t.First, t.Second, t.Third)
+}
@@ -0,0 +1,28 @@
+package tuple
+
+import (
+	"testing"
+)
+
+func TestPair(t *testing.T) {
+	p := MakePair(1, "one")
+	if a, b := p.Values(); a != 1 || b != "one" {
+		t.Errorf("Values() got=(%v, %v) want=(1, one)", a, b)
+	}
+	if p != (Pair[int, string]{1, "one"}) {
+		t.Errorf("pairs with equal elements must be equal")
+	}
+	if s := p.String(); s != "(1, one)" {
+		t.Errorf("String() got=%q want=%q", s, "(1, one)")
+	}
+}
+
+func TestTriple(t *testing.T) {
+	p := MakeTriple(1, "one", 1.5)
+	if a, b, c := p.Values(); a != 1 || b != "one" || c != 1.5 {
+		t.Errorf("Values() got=(%v, %v, %v) want=(1, one, 1.5)", a, b, c)
+	}
+	if s := p.String(); s != "(1, one, 1.5)" {
+		t.Errorf("String() got=%q want=%q", s, "(1, one, 1.5)")
+	}
+}